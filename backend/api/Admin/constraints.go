@@ -0,0 +1,118 @@
+// Package admin implements the endpoints that manage the constraints and
+// indexes of the database.
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type CreateConstraintRequest struct {
+	// Name is generated from the label and properties when empty.
+	Name       string   `json:"Name,omitempty"`
+	Label      string   `json:"Label"`
+	Properties []string `json:"Properties"`
+}
+
+// NewListConstraintsHandler handles `GET /admin/constraints`.
+func NewListConstraintsHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		constraints, err := db.Constraints(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error listando constraints")
+			apierror.StoreError(w, r, err, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.Constraints(constraints))
+	}
+}
+
+// NewCreateConstraintHandler handles `POST /admin/constraints`, which makes
+// the properties unique among the nodes with the label. It answers 201 when
+// the constraint is created and 200 when an equivalent one already existed.
+func NewCreateConstraintHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CreateConstraintRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
+		}
+
+		spec, ok := readSpec(w, r, req.Name, req.Label, req.Properties)
+		if !ok {
+			return
+		}
+
+		log.Info().Str("label", spec.Label).Strs("properties", spec.Properties).Msg("⏳ Creando constraint...")
+		change, err := db.CreateUniqueConstraint(r.Context(), spec)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error creando el constraint")
+			apierror.StoreError(w, r, err, "")
+			return
+		}
+
+		writeChange(w, change)
+	}
+}
+
+// NewDropConstraintHandler handles `DELETE /admin/constraints/{name}`.
+func NewDropConstraintHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+			apierror.InvalidField(w, r, "name", err)
+			return
+		}
+
+		if err := db.DropConstraint(r.Context(), name); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("❌ Error eliminando el constraint")
+			apierror.StoreError(w, r, err, fmt.Sprintf("No constraint named `%s` exists", name))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.DeletedDTO{DeletedCount: 1})
+	}
+}
+
+// readSpec validates the fields shared by constraints and indexes. If they're
+// invalid an error response is sent and ok is false.
+func readSpec(w http.ResponseWriter, r *http.Request, name string, label string, properties []string) (spec store.IndexSpec, ok bool) {
+	if label == "" || len(properties) == 0 {
+		apierror.MissingFields(w, r, "Label", "Properties")
+		return spec, false
+	}
+	if name != "" {
+		if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+			apierror.InvalidField(w, r, "Name", err)
+			return spec, false
+		}
+	}
+	if err := identifier.Validate(identifier.KindLabel, label); err != nil {
+		apierror.InvalidField(w, r, "Label", err)
+		return spec, false
+	}
+	if err := identifier.PropertyKeyList(properties); err != nil {
+		apierror.InvalidField(w, r, "Properties", err)
+		return spec, false
+	}
+	return store.IndexSpec{Name: name, Label: label, Properties: properties}, true
+}
+
+func writeChange(w http.ResponseWriter, change store.SchemaChange) {
+	w.Header().Set("Content-Type", "application/json")
+	if change.Created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(dto.SchemaChange(change))
+}
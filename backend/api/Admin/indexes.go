@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type CreateIndexRequest struct {
+	// Name is generated from the label, properties and type when empty.
+	Name string `json:"Name,omitempty"`
+	// Type is `RANGE` (the default) or `TEXT`.
+	Type       string   `json:"Type,omitempty"`
+	Label      string   `json:"Label"`
+	Properties []string `json:"Properties"`
+}
+
+// NewListIndexesHandler handles `GET /admin/indexes`.
+func NewListIndexesHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		indexes, err := db.Indexes(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error listando índices")
+			apierror.StoreError(w, r, err, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.Indexes(indexes))
+	}
+}
+
+// NewCreateIndexHandler handles `POST /admin/indexes`. It answers 201 when the
+// index is created and 200 when an equivalent one already existed.
+func NewCreateIndexHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CreateIndexRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
+		}
+
+		spec, ok := readSpec(w, r, req.Name, req.Label, req.Properties)
+		if !ok {
+			return
+		}
+
+		spec.Type = store.IndexType(strings.ToUpper(req.Type))
+		switch spec.Type {
+		case "":
+			spec.Type = store.IndexRange
+		case store.IndexRange:
+		case store.IndexText:
+			if len(spec.Properties) > 1 {
+				apierror.InvalidField(w, r, "Properties", errors.New("a TEXT index can only have one property"))
+				return
+			}
+		default:
+			apierror.InvalidField(w, r, "Type", fmt.Errorf("it must be `%s` or `%s`", store.IndexRange, store.IndexText))
+			return
+		}
+
+		log.Info().Str("label", spec.Label).Strs("properties", spec.Properties).Str("type", string(spec.Type)).Msg("⏳ Creando índice...")
+		change, err := db.CreateIndex(r.Context(), spec)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error creando el índice")
+			apierror.StoreError(w, r, err, "")
+			return
+		}
+
+		writeChange(w, change)
+	}
+}
+
+// NewDropIndexHandler handles `DELETE /admin/indexes/{name}`. Indexes backing a
+// constraint are dropped by dropping the constraint.
+func NewDropIndexHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+			apierror.InvalidField(w, r, "name", err)
+			return
+		}
+
+		if err := db.DropIndex(r.Context(), name); err != nil {
+			log.Error().Err(err).Str("name", name).Msg("❌ Error eliminando el índice")
+			apierror.StoreError(w, r, err, fmt.Sprintf("No index named `%s` exists", name))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.DeletedDTO{DeletedCount: 1})
+	}
+}
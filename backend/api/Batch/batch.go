@@ -0,0 +1,373 @@
+// Package batch implements `POST /batch`, which applies an ordered list of
+// operations in a single write transaction.
+//
+// Operations name what they create with `Ref` so later operations can use it:
+//
+//	{"Operations": [
+//		{"Op": "createNode", "Ref": "chair", "NodeType": "Product", "Properties": {"id": "P9"}},
+//		{"Op": "createRelation", "Ref": "makes", "RelationType": "PRODUCES",
+//			"Origin": {"ElementId": "4:...:1"}, "Destination": {"Ref": "chair"}, "Properties": {}},
+//		{"Op": "removeNodeProperties", "Target": {"Ref": "chair"}, "Keys": ["draft"]}
+//	]}
+//
+// If any operation fails nothing is written. Operations are checked against the
+// schema registry inside the transaction, so updates see the labels and types
+// of elements created earlier in the batch.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+// MaxOperations is the most operations a single batch may have.
+const MaxOperations = 500
+
+const (
+	OpCreateNode               = "createNode"
+	OpUpdateNode               = "updateNode"
+	OpDeleteNode               = "deleteNode"
+	OpRemoveNodeProperties     = "removeNodeProperties"
+	OpCreateRelation           = "createRelation"
+	OpUpdateRelation           = "updateRelation"
+	OpDeleteRelation           = "deleteRelation"
+	OpRemoveRelationProperties = "removeRelationProperties"
+)
+
+// Reference points to a node or relationship, either one that already exists
+// by its element ID or one created earlier in the batch by its `Ref`.
+type Reference struct {
+	Ref       string `json:"Ref,omitempty"`
+	ElementId string `json:"ElementId,omitempty"`
+}
+
+type Operation struct {
+	Op string `json:"Op"`
+	// Ref names the node or relationship created by this operation.
+	Ref          string         `json:"Ref,omitempty"`
+	NodeType     string         `json:"NodeType,omitempty"`
+	RelationType string         `json:"RelationType,omitempty"`
+	Target       *Reference     `json:"Target,omitempty"`
+	Origin       *Reference     `json:"Origin,omitempty"`
+	Destination  *Reference     `json:"Destination,omitempty"`
+	Properties   map[string]any `json:"Properties,omitempty"`
+	Keys         []string       `json:"Keys,omitempty"`
+}
+
+type BatchRequest struct {
+	Operations []Operation `json:"Operations"`
+}
+
+// operationError is returned when the operation at Index fails.
+type operationError struct {
+	Index int
+	Err   error
+}
+
+func (e *operationError) Error() string {
+	return fmt.Sprintf("`Operations[%d]`: %s", e.Index, e.Err.Error())
+}
+
+func (e *operationError) Unwrap() error {
+	return e.Err
+}
+
+// NewBatchHandler handles `POST /batch`.
+func NewBatchHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var req BatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
+		}
+
+		if len(req.Operations) == 0 {
+			apierror.MissingFields(w, r, "Operations")
+			return
+		}
+		if len(req.Operations) > MaxOperations {
+			apierror.InvalidField(w, r, "Operations", fmt.Errorf("a batch can't have more than %d operations", MaxOperations))
+			return
+		}
+
+		if err := validate(req.Operations); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		log.Info().Int("operations", len(req.Operations)).Msg("⏳ Ejecutando batch...")
+		var results []dto.BatchResultDTO
+		err := db.WriteTransaction(ctx, func(tx store.GraphStore) error {
+			var err error
+			results, err = apply(ctx, tx, registry, req.Operations)
+			return err
+		})
+
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Batch rolled back")
+			var opErr *operationError
+			if !errors.As(err, &opErr) {
+				apierror.DBError(w, r, err)
+				return
+			}
+
+			field := fmt.Sprintf("Operations[%d]", opErr.Index)
+			var schemaErr *schema.Error
+			if errors.As(err, &schemaErr) {
+				apierror.SchemaViolation(w, r, field, schemaErr)
+				return
+			}
+			details := apierror.FieldDetails{Field: field, Reason: opErr.Err.Error()}
+			if errors.Is(err, store.ErrNotFound) {
+				apierror.Write(w, r, http.StatusNotFound, apierror.CodeNotFound, fmt.Sprintf("`%s` references an element that doesn't exist, nothing was written", field), details)
+				return
+			}
+			apierror.Write(w, r, http.StatusInternalServerError, apierror.CodeDBError, fmt.Sprintf("`%s` failed, nothing was written", field), details)
+			return
+		}
+
+		log.Info().Int("operations", len(results)).Msg("✅ Batch committed")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.BatchDTO{Results: results})
+	}
+}
+
+// validate checks every operation before anything is written, references
+// must point to an element of the right kind created by an earlier operation.
+func validate(operations []Operation) error {
+	// refs maps every `Ref` to whether it names a node.
+	refs := make(map[string]bool)
+
+	for i, op := range operations {
+		if field, err := validateOperation(op, refs); err != nil {
+			return &utils.FieldError{Field: fmt.Sprintf("Operations[%d].%s", i, field), Err: err}
+		}
+		if op.Ref != "" {
+			refs[op.Ref] = op.Op == OpCreateNode
+		}
+	}
+	return nil
+}
+
+// validateOperation returns the invalid field of op and why it's invalid.
+func validateOperation(op Operation, refs map[string]bool) (string, error) {
+	if op.Ref != "" {
+		if op.Op != OpCreateNode && op.Op != OpCreateRelation {
+			return "Ref", errors.New("only create operations can name what they create")
+		}
+		if _, found := refs[op.Ref]; found {
+			return "Ref", fmt.Errorf("`%s` is already used by an earlier operation", op.Ref)
+		}
+	}
+
+	switch op.Op {
+	case OpCreateNode:
+		if err := identifier.Validate(identifier.KindLabel, op.NodeType); err != nil {
+			return "NodeType", err
+		}
+		return validateProperties(op.Properties, false)
+
+	case OpCreateRelation:
+		if err := identifier.Validate(identifier.KindRelationType, op.RelationType); err != nil {
+			return "RelationType", err
+		}
+		if err := validateReference(op.Origin, true, refs); err != nil {
+			return "Origin", err
+		}
+		if err := validateReference(op.Destination, true, refs); err != nil {
+			return "Destination", err
+		}
+		return validateProperties(op.Properties, false)
+
+	case OpUpdateNode, OpUpdateRelation:
+		if err := validateReference(op.Target, op.Op == OpUpdateNode, refs); err != nil {
+			return "Target", err
+		}
+		return validateProperties(op.Properties, true)
+
+	case OpRemoveNodeProperties, OpRemoveRelationProperties:
+		if err := validateReference(op.Target, op.Op == OpRemoveNodeProperties, refs); err != nil {
+			return "Target", err
+		}
+		if len(op.Keys) == 0 {
+			return "Keys", errors.New("it's required")
+		}
+		if err := identifier.PropertyKeyList(op.Keys); err != nil {
+			return "Keys", err
+		}
+		return "", nil
+
+	case OpDeleteNode, OpDeleteRelation:
+		if err := validateReference(op.Target, op.Op == OpDeleteNode, refs); err != nil {
+			return "Target", err
+		}
+		return "", nil
+	}
+	return "Op", fmt.Errorf("unknown operation `%s`", op.Op)
+}
+
+func validateReference(ref *Reference, isNode bool, refs map[string]bool) error {
+	if ref == nil || (ref.Ref == "") == (ref.ElementId == "") {
+		return errors.New("it must have either `Ref` or `ElementId`")
+	}
+	if ref.Ref == "" {
+		return nil
+	}
+
+	refIsNode, found := refs[ref.Ref]
+	if !found {
+		return fmt.Errorf("`%s` isn't created by an earlier operation", ref.Ref)
+	}
+	if refIsNode != isNode {
+		kind := "relationship"
+		if isNode {
+			kind = "node"
+		}
+		return fmt.Errorf("`%s` doesn't reference a %s", ref.Ref, kind)
+	}
+	return nil
+}
+
+func validateProperties(properties map[string]any, required bool) (string, error) {
+	if required && len(properties) == 0 {
+		return "Properties", errors.New("it's required")
+	}
+	if err := identifier.PropertyKeys(properties); err != nil {
+		return "Properties", err
+	}
+	return "", nil
+}
+
+// apply runs the already validated operations in order.
+func apply(ctx context.Context, tx store.GraphStore, registry *schema.Registry, operations []Operation) ([]dto.BatchResultDTO, error) {
+	// ids maps every `Ref` to the element ID of what it created.
+	ids := make(map[string]string)
+	resolve := func(ref *Reference) string {
+		if ref.Ref != "" {
+			return ids[ref.Ref]
+		}
+		return ref.ElementId
+	}
+
+	results := make([]dto.BatchResultDTO, 0, len(operations))
+	for i, op := range operations {
+		result := dto.BatchResultDTO{Op: op.Op, Ref: op.Ref}
+
+		if registry != nil {
+			if err := checkSchema(ctx, tx, registry, op, resolve); err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+		}
+
+		switch op.Op {
+		case OpCreateNode:
+			node, err := tx.CreateNode(ctx, op.NodeType, op.Properties)
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			ids[op.Ref] = node.ElementId
+			result.Node = nodeResult(node)
+		case OpUpdateNode, OpRemoveNodeProperties:
+			update, err := tx.UpdateNode(ctx, resolve(op.Target), changes(op))
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			result.Node = nodeResult(update.Node)
+		case OpDeleteNode:
+			node, err := tx.DeleteNode(ctx, resolve(op.Target))
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			result.Node = nodeResult(node)
+		case OpCreateRelation:
+			match, err := tx.CreateRelationBetween(ctx, resolve(op.Origin), resolve(op.Destination), op.RelationType, op.Properties)
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			ids[op.Ref] = match.Relation.ElementId
+			result.Relation = relationResult(match.Relation)
+		case OpUpdateRelation, OpRemoveRelationProperties:
+			relation, err := tx.UpdateRelation(ctx, resolve(op.Target), changes(op))
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			result.Relation = relationResult(relation)
+		case OpDeleteRelation:
+			relation, err := tx.DeleteRelation(ctx, resolve(op.Target))
+			if err != nil {
+				return nil, &operationError{Index: i, Err: err}
+			}
+			result.Relation = relationResult(relation)
+		}
+
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// checkSchema checks op against the registry, reading the elements it touches
+// to learn their labels or type.
+func checkSchema(ctx context.Context, tx store.GraphStore, registry *schema.Registry, op Operation, resolve func(*Reference) string) error {
+	switch op.Op {
+	case OpCreateNode:
+		return registry.CheckNode([]string{op.NodeType}, op.Properties)
+	case OpUpdateNode, OpRemoveNodeProperties:
+		node, err := tx.GetNode(ctx, resolve(op.Target))
+		if err != nil {
+			return err
+		}
+		return registry.CheckNodeUpdate(node.Labels, changes(op))
+	case OpCreateRelation:
+		origin, err := tx.GetNode(ctx, resolve(op.Origin))
+		if err != nil {
+			return err
+		}
+		destination, err := tx.GetNode(ctx, resolve(op.Destination))
+		if err != nil {
+			return err
+		}
+		return registry.CheckRelation(op.RelationType, origin.Labels, destination.Labels, op.Properties)
+	case OpUpdateRelation, OpRemoveRelationProperties:
+		match, err := tx.GetRelation(ctx, resolve(op.Target))
+		if err != nil {
+			return err
+		}
+		return registry.CheckRelationUpdate(match.Relation.Type, changes(op))
+	}
+	return nil
+}
+
+// changes returns the properties an update sets, removed keys are set to nil.
+func changes(op Operation) map[string]any {
+	if op.Op != OpRemoveNodeProperties && op.Op != OpRemoveRelationProperties {
+		return op.Properties
+	}
+	removed := make(map[string]any, len(op.Keys))
+	for _, key := range op.Keys {
+		removed[key] = nil
+	}
+	return removed
+}
+
+func nodeResult(node store.Node) *dto.NodeDTO {
+	converted := dto.Node(node)
+	return &converted
+}
+
+func relationResult(relation store.Relationship) *dto.RelationDTO {
+	converted := dto.Relation(relation)
+	return &converted
+}
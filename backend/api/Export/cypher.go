@@ -0,0 +1,95 @@
+package export
+
+import (
+	"io"
+	"maps"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+const (
+	// exportLabel and exportIdKey temporarily mark the imported nodes so the
+	// relationships can find them, the script removes them at the end.
+	exportLabel = "__Export__"
+	exportIdKey = "__exportId"
+	exportIndex = "export_id"
+)
+
+// cypherExporter writes a script that recreates the graph when it's run, for
+// example with `cypher-shell -f export.cypher`.
+type cypherExporter struct {
+	out errWriter
+}
+
+func newCypherExporter(w io.Writer) *cypherExporter {
+	return &cypherExporter{out: errWriter{w: w}}
+}
+
+func (c *cypherExporter) begin() error {
+	c.out.write(
+		"// Recreates the exported graph, run it with `cypher-shell -f`.\n",
+		"CREATE INDEX ", identifier.Escape(exportIndex), " IF NOT EXISTS FOR (n:", identifier.Escape(exportLabel), ") ON (n.", identifier.Escape(exportIdKey), ");\n",
+		"CALL db.awaitIndexes();\n",
+	)
+	return c.out.err
+}
+
+func (c *cypherExporter) node(node store.Node) error {
+	labels := ""
+	for _, label := range node.Labels {
+		escaped, err := identifier.Label(label)
+		if err != nil {
+			return err
+		}
+		labels += ":" + escaped
+	}
+	labels += ":" + identifier.Escape(exportLabel)
+
+	properties := maps.Clone(node.Props)
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	properties[exportIdKey] = node.ElementId
+	literal, err := cypher.MapLiteral(properties)
+	if err != nil {
+		return err
+	}
+
+	c.out.write("CREATE (", labels, " ", literal, ");\n")
+	return c.out.err
+}
+
+func (c *cypherExporter) relation(relation store.Relationship) error {
+	relType, err := identifier.RelationType(relation.Type)
+	if err != nil {
+		return err
+	}
+	literal, err := cypher.MapLiteral(relation.Props)
+	if err != nil {
+		return err
+	}
+
+	c.out.write(
+		"MATCH ", exportedNode("n1", relation.StartElementId), ", ", exportedNode("n2", relation.EndElementId),
+		" CREATE (n1)-[:", relType, " ", literal, "]->(n2);\n",
+	)
+	return c.out.err
+}
+
+func exportedNode(variable string, elementId string) string {
+	id, _ := cypher.Literal(elementId)
+	return "(" + variable + ":" + identifier.Escape(exportLabel) + " {" + identifier.Escape(exportIdKey) + ": " + id + "})"
+}
+
+// end removes the markers with a plain statement, `CALL { ... } IN
+// TRANSACTIONS` would need `:auto` and only cypher-shell understands it.
+func (c *cypherExporter) end() error {
+	label, key := identifier.Escape(exportLabel), identifier.Escape(exportIdKey)
+	c.out.write(
+		"MATCH (n:", label, ") REMOVE n:", label, ", n.", key, ";\n",
+		"DROP INDEX ", identifier.Escape(exportIndex), " IF EXISTS;\n",
+	)
+	return c.out.err
+}
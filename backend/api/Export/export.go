@@ -0,0 +1,177 @@
+// Package export implements `GET /export`, which streams a snapshot of the
+// graph as JSON, GraphML or a Cypher script.
+//
+// The `labels` and `types` URL queries are comma separated lists that
+// restrict the exported nodes and relationships. Relationships are only
+// exported when both of their nodes are.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatGraphML Format = "graphml"
+	FormatCypher  Format = "cypher"
+)
+
+// bufferSize is how much of the export is buffered before being sent.
+const bufferSize = 32 * 1024
+
+// exporter writes the elements of the graph in a format.
+type exporter interface {
+	begin() error
+	node(store.Node) error
+	relation(store.Relationship) error
+	end() error
+}
+
+type formatInfo struct {
+	contentType string
+	extension   string
+}
+
+var formats = map[Format]formatInfo{
+	FormatJSON:    {contentType: "application/json", extension: "json"},
+	FormatGraphML: {contentType: "application/graphml+xml", extension: "graphml"},
+	FormatCypher:  {contentType: "text/plain; charset=utf-8", extension: "cypher"},
+}
+
+// NewExportHandler handles `GET /export?format=json|graphml|cypher&labels=...&types=...`.
+func NewExportHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		url_queries := r.URL.Query()
+
+		format := Format(url_queries.Get("format"))
+		if format == "" {
+			format = FormatJSON
+		}
+		info, known := formats[format]
+		if !known {
+			apierror.InvalidField(w, r, "format", fmt.Errorf("`%s` isn't one of json, graphml or cypher", format))
+			return
+		}
+
+		filter := store.ExportFilter{}
+		var err error
+		if filter.Labels, err = readList(url_queries.Get("labels"), "labels", identifier.KindLabel); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+		if filter.Types, err = readList(url_queries.Get("types"), "types", identifier.KindRelationType); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		// Nothing reaches the client until the buffer fills up, so an error
+		// found early can still be answered with an error response.
+		sent := &countingWriter{w: w}
+		buffer := bufio.NewWriterSize(sent, bufferSize)
+
+		// Every pass reads the same snapshot, so the GraphML keys are those of
+		// the exported elements.
+		err = db.ReadTransaction(ctx, func(tx store.GraphStore) error {
+			var out exporter
+			switch format {
+			case FormatJSON:
+				out = newJSONExporter(buffer)
+			case FormatGraphML:
+				// GraphML declares every property key before the graph, which
+				// takes a first pass over the elements.
+				keys := newGraphMLKeys()
+				if err := tx.Export(ctx, filter, keys.node, keys.relation); err != nil {
+					return err
+				}
+				out = newGraphMLExporter(buffer, keys)
+			case FormatCypher:
+				out = newCypherExporter(buffer)
+			}
+
+			w.Header().Set("Content-Type", info.contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="export.%s"`, info.extension))
+
+			log.Info().Str("format", string(format)).Strs("labels", filter.Labels).Strs("types", filter.Types).Msg("⏳ Exportando grafo...")
+			if err := out.begin(); err != nil {
+				return err
+			}
+			if err := tx.Export(ctx, filter, out.node, out.relation); err != nil {
+				return err
+			}
+			if err := out.end(); err != nil {
+				return err
+			}
+			return buffer.Flush()
+		})
+
+		if err != nil {
+			log.Error().Err(err).Int64("sentBytes", sent.n).Msg("❌ Error exportando el grafo")
+			if sent.n == 0 {
+				w.Header().Del("Content-Disposition")
+				apierror.DBError(w, r, err)
+			}
+			// Otherwise the client already got part of the export, ending the
+			// response early is the only way left to tell it's incomplete.
+			return
+		}
+		log.Info().Int64("sentBytes", sent.n).Msg("✅ Grafo exportado")
+	}
+}
+
+// readList splits a comma separated URL query and validates every item.
+func readList(value string, field string, kind identifier.Kind) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if err := identifier.Validate(kind, item); err != nil {
+			return nil, &utils.FieldError{Field: field, Err: err}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// countingWriter counts the bytes sent to the client.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// errWriter remembers the first write error, so the exporters can write
+// several pieces and check for an error once.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) write(parts ...string) {
+	for _, part := range parts {
+		if e.err != nil {
+			return
+		}
+		_, e.err = io.WriteString(e.w, part)
+	}
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+const graphMLHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+`
+
+// graphMLKeys collects the property keys of the nodes and the relationships
+// along with their GraphML type. A key holding values of different types is
+// declared as a string.
+type graphMLKeys struct {
+	nodes     map[string]string
+	relations map[string]string
+}
+
+func newGraphMLKeys() *graphMLKeys {
+	return &graphMLKeys{nodes: make(map[string]string), relations: make(map[string]string)}
+}
+
+func (k *graphMLKeys) node(node store.Node) error {
+	addKeys(k.nodes, node.Props)
+	return nil
+}
+
+func (k *graphMLKeys) relation(relation store.Relationship) error {
+	addKeys(k.relations, relation.Props)
+	return nil
+}
+
+func addKeys(keys map[string]string, properties map[string]any) {
+	for key, value := range properties {
+		attrType := graphMLType(value)
+		if previous, found := keys[key]; found && previous != attrType {
+			attrType = "string"
+		}
+		keys[key] = attrType
+	}
+}
+
+func graphMLType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case int, int32, int64:
+		return "long"
+	case float32, float64:
+		return "double"
+	}
+	return "string"
+}
+
+// graphMLExporter writes nodes and relationships like APOC's GraphML export,
+// labels are joined as `:Label1:Label2`.
+type graphMLExporter struct {
+	out   errWriter
+	types *graphMLKeys
+	// nodeIds and relationIds map every property key to its GraphML key id.
+	nodeIds     map[string]string
+	relationIds map[string]string
+	inGraph     bool
+}
+
+func newGraphMLExporter(w io.Writer, keys *graphMLKeys) *graphMLExporter {
+	return &graphMLExporter{
+		out:         errWriter{w: w},
+		types:       keys,
+		nodeIds:     keyIds("n", keys.nodes),
+		relationIds: keyIds("e", keys.relations),
+	}
+}
+
+// keyIds numbers the keys in order, so property names never clash with the
+// `labels` and `label` keys.
+func keyIds(prefix string, types map[string]string) map[string]string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := make(map[string]string, len(names))
+	for i, name := range names {
+		ids[name] = prefix + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func (g *graphMLExporter) begin() error {
+	g.out.write(graphMLHeader)
+	g.out.write(`<key id="labels" for="node" attr.name="labels" attr.type="string"/>`, "\n")
+	g.out.write(`<key id="label" for="edge" attr.name="label" attr.type="string"/>`, "\n")
+	g.declare("node", g.nodeIds, g.types.nodes)
+	g.declare("edge", g.relationIds, g.types.relations)
+	g.out.write(`<graph id="G" edgedefault="directed">`, "\n")
+	return g.out.err
+}
+
+func (g *graphMLExporter) declare(kind string, ids map[string]string, types map[string]string) {
+	names := make([]string, 0, len(ids))
+	for name := range ids {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return ids[names[i]] < ids[names[j]] })
+
+	for _, name := range names {
+		g.out.write(fmt.Sprintf(`<key id="%s" for="%s" attr.name="%s" attr.type="%s"/>`, ids[name], kind, escape(name), types[name]), "\n")
+	}
+}
+
+func (g *graphMLExporter) node(node store.Node) error {
+	labels := ""
+	if len(node.Labels) > 0 {
+		labels = ":" + strings.Join(node.Labels, ":")
+	}
+	g.out.write(`<node id="`, escape(node.ElementId), `" labels="`, escape(labels), `">`)
+	g.out.write(`<data key="labels">`, escape(labels), `</data>`)
+	g.data(g.nodeIds, node.Props)
+	g.out.write("</node>\n")
+	return g.out.err
+}
+
+func (g *graphMLExporter) relation(relation store.Relationship) error {
+	g.out.write(`<edge id="`, escape(relation.ElementId), `" source="`, escape(relation.StartElementId),
+		`" target="`, escape(relation.EndElementId), `" label="`, escape(relation.Type), `">`)
+	g.out.write(`<data key="label">`, escape(relation.Type), `</data>`)
+	g.data(g.relationIds, relation.Props)
+	g.out.write("</edge>\n")
+	return g.out.err
+}
+
+func (g *graphMLExporter) data(ids map[string]string, properties map[string]any) {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		id := ids[key]
+		g.out.write(`<data key="`, id, `">`, escape(propertyText(properties[key])), `</data>`)
+	}
+}
+
+func (g *graphMLExporter) end() error {
+	g.out.write("</graph>\n</graphml>\n")
+	return g.out.err
+}
+
+// propertyText writes a property value as GraphML data, lists are written as JSON.
+func propertyText(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
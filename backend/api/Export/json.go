@@ -0,0 +1,59 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// jsonExporter writes `{"nodes": [...], "relationships": [...]}` with the
+// same node and relationship objects the rest of the API answers with.
+type jsonExporter struct {
+	out       errWriter
+	encoder   *json.Encoder
+	count     int
+	relations bool
+}
+
+func newJSONExporter(w io.Writer) *jsonExporter {
+	return &jsonExporter{out: errWriter{w: w}, encoder: json.NewEncoder(w)}
+}
+
+func (j *jsonExporter) begin() error {
+	j.out.write(`{"nodes":[`)
+	return j.out.err
+}
+
+func (j *jsonExporter) node(node store.Node) error {
+	return j.item(dto.Node(node))
+}
+
+func (j *jsonExporter) relation(relation store.Relationship) error {
+	if !j.relations {
+		j.relations = true
+		j.count = 0
+		j.out.write(`],"relationships":[`)
+	}
+	return j.item(dto.Relation(relation))
+}
+
+func (j *jsonExporter) item(value any) error {
+	if j.count > 0 {
+		j.out.write(",")
+	}
+	j.count++
+	if j.out.err != nil {
+		return j.out.err
+	}
+	return j.encoder.Encode(value)
+}
+
+func (j *jsonExporter) end() error {
+	if !j.relations {
+		j.out.write(`],"relationships":[`)
+	}
+	j.out.write("]}\n")
+	return j.out.err
+}
@@ -0,0 +1,116 @@
+package functionalrequirements
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultBOMDepth is how many `NEEDS` levels are followed when the
+	// `Depth` URL query isn't sent.
+	DefaultBOMDepth = 3
+	MaxBOMDepth     = 10
+)
+
+// BOMItem is a product or material along with its providers and the
+// materials it needs. Needs has the properties of the `NEEDS` relationship
+// from its parent. Cycle is set when the material is already one of its
+// ancestors, so its materials aren't listed again.
+type BOMItem struct {
+	Node      dto.NodeDTO    `json:"node"`
+	Needs     map[string]any `json:"needs,omitempty"`
+	Providers []dto.NodeDTO  `json:"providers"`
+	Materials []BOMItem      `json:"materials"`
+	Cycle     bool           `json:"cycle,omitempty"`
+}
+
+// BillOfMaterialsResponse is the tree of materials of a product.
+type BillOfMaterialsResponse struct {
+	Depth   int     `json:"depth"`
+	Product BOMItem `json:"product"`
+}
+
+// NewGetBillOfMaterialsHandler handles `GET /history/bom`, which follows the
+// `NEEDS` relationships of the `ProductId` product up to `Depth` levels.
+func NewGetBillOfMaterialsHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queries := r.URL.Query()
+		productId := queries.Get("ProductId")
+		if productId == "" {
+			apierror.MissingFields(w, r, "ProductId")
+			return
+		}
+
+		depth := DefaultBOMDepth
+		if raw := queries.Get("Depth"); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value <= 0 || value > MaxBOMDepth {
+				apierror.InvalidField(w, r, "Depth", fmt.Errorf("it must be an integer between 1 and %d", MaxBOMDepth))
+				return
+			}
+			depth = value
+		}
+
+		log.Info().Str("productId", productId).Int("depth", depth).Msg("⏳ Recorriendo la lista de materiales...")
+		bom, err := db.BillOfMaterials(r.Context(), productId, depth)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error recorriendo la lista de materiales")
+			apierror.StoreError(w, r, err, fmt.Sprintf("No product with id `%s` exists", productId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(BillOfMaterialsResponse{Depth: depth, Product: bomTree(bom, depth)})
+	}
+}
+
+// bomTree unfolds the bill of materials into a tree, a material needed by
+// several others appears under each of them.
+func bomTree(bom store.BillOfMaterials, depth int) BOMItem {
+	nodes := map[string]store.Node{bom.Product.ElementId: bom.Product}
+	for _, material := range bom.Materials {
+		nodes[material.ElementId] = material
+	}
+	needs := make(map[string][]store.Relationship)
+	for _, relation := range bom.Needs {
+		needs[relation.StartElementId] = append(needs[relation.StartElementId], relation)
+	}
+
+	ancestors := make(map[string]bool)
+	var unfold func(node store.Node, level int) BOMItem
+	unfold = func(node store.Node, level int) BOMItem {
+		item := BOMItem{
+			Node:      dto.Node(node),
+			Providers: dto.Nodes(bom.Providers[node.ElementId]),
+			Materials: []BOMItem{},
+		}
+		if ancestors[node.ElementId] {
+			item.Cycle = true
+			return item
+		}
+		if level == depth {
+			return item
+		}
+
+		ancestors[node.ElementId] = true
+		for _, relation := range needs[node.ElementId] {
+			material, found := nodes[relation.EndElementId]
+			if !found {
+				continue
+			}
+			child := unfold(material, level+1)
+			child.Needs = relation.Props
+			item.Materials = append(item.Materials, child)
+		}
+		delete(ancestors, node.ElementId)
+		return item
+	}
+	return unfold(bom.Product, 0)
+}
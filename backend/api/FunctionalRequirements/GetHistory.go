@@ -2,67 +2,83 @@ package functionalrequirements
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
-func NewGetHistoryHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+// HistoryResponse is the graph formed by every path from the providers to
+// the product, each node and relationship is listed once.
+type HistoryResponse struct {
+	Nodes         []dto.NodeDTO     `json:"nodes"`
+	Relationships []dto.RelationDTO `json:"relationships"`
+}
+
+func NewGetHistoryHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 		queries := r.URL.Query()
 		productId := queries.Get("ProductId")
 		w.Header().Add("Access-Control-Allow-Origin", "*")
 
 		if productId == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("BAD REQUEST - NO `ProductId` query!"))
+			apierror.MissingFields(w, r, "ProductId")
 			return
 		}
 
-		query := `MATCH p1=(:Provider)-[:PRODUCES]->(:Product {id: $id})
-RETURN p1
-UNION
-MATCH p1=(:Provider)-[:PRODUCES]->(:Material)<-[:NEEDS]-(:Product {id: $id})
-RETURN p1`
-
-		params := make(map[string]any)
-		params["id"] = productId
-
-		log.Info().Str("query", query).Msg("Querying DB...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		paths, err := db.ProductHistory(ctx, productId)
 
 		if err != nil {
 			log.Error().Err(err).Msg("Error querying DB!")
-			w.WriteHeader(http.StatusInternalServerError)
-			msg := fmt.Sprintf("INTERNAL SERVER ERROR - QUERY ERROR `%s`", err.Error())
-			w.Write([]byte(msg))
+			apierror.DBError(w, r, err)
 			return
 		}
 
-		if len(result.Records) == 0 {
+		if len(paths) == 0 {
 			log.Error().Err(err).Msg("No records found!")
-			w.WriteHeader(http.StatusNotFound)
-			msg := fmt.Sprintf("NOT FOUND - No product with id `%s` exists!", productId)
-			w.Write([]byte(msg))
+			apierror.NotFound(w, r, fmt.Sprintf("No product with id `%s` exists", productId))
 			return
 		}
 
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records[0])
+		record := historyGraph(paths)
+		log.Info().Int("paths", len(paths)).Int("nodes", len(record.Nodes)).Int("relationships", len(record.Relationships)).Msg("Done!")
+		err = enc.Encode(record)
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("row", record).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
 		w.Write(buff.Bytes())
 	}
 }
+
+// historyGraph merges the paths, keeping the order in which nodes and
+// relationships are first seen.
+func historyGraph(paths []store.Path) HistoryResponse {
+	graph := HistoryResponse{Nodes: []dto.NodeDTO{}, Relationships: []dto.RelationDTO{}}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		for _, node := range path.Nodes {
+			if !seen[node.ElementId] {
+				seen[node.ElementId] = true
+				graph.Nodes = append(graph.Nodes, dto.Node(node))
+			}
+		}
+		for _, relation := range path.Relationships {
+			if !seen[relation.ElementId] {
+				seen[relation.ElementId] = true
+				graph.Relationships = append(graph.Relationships, dto.Relation(relation))
+			}
+		}
+	}
+	return graph
+}
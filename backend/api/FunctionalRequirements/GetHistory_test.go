@@ -0,0 +1,93 @@
+package functionalrequirements
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+)
+
+// historyFixture has two providers producing two materials the product
+// needs, so the provider to product paths share nodes and relationships.
+const historyFixture = `{
+	"nodes": [
+		{"key": "pr1", "labels": ["Provider"], "properties": {"id": "PR1"}},
+		{"key": "pr2", "labels": ["Provider"], "properties": {"id": "PR2"}},
+		{"key": "m1", "labels": ["Material"], "properties": {"id": "M1"}},
+		{"key": "m2", "labels": ["Material"], "properties": {"id": "M2"}},
+		{"key": "p1", "labels": ["Product"], "properties": {"id": "P1"}},
+		{"key": "p2", "labels": ["Product"], "properties": {"id": "P2"}}
+	],
+	"relations": [
+		{"type": "PRODUCES", "from": "pr1", "to": "m1", "properties": {}},
+		{"type": "PRODUCES", "from": "pr1", "to": "m2", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m1", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "p1", "properties": {}},
+		{"type": "NEEDS", "from": "p1", "to": "m1", "properties": {"quantity": 2}},
+		{"type": "NEEDS", "from": "p1", "to": "m2", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p2", "to": "m2", "properties": {"quantity": 4}}
+	]
+}`
+
+func TestHistoryGraph(t *testing.T) {
+	db := memstore.New()
+	if err := db.Seed(strings.NewReader(historyFixture)); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := db.ProductHistory(context.Background(), "P1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("got %d paths, want 4", len(paths))
+	}
+
+	// Every node and relationship of the paths, with how often it was seen.
+	wantNodes := make(map[string]int)
+	wantRelationships := make(map[string]int)
+	for _, path := range paths {
+		for _, node := range path.Nodes {
+			wantNodes[node.ElementId]++
+		}
+		for _, relation := range path.Relationships {
+			wantRelationships[relation.ElementId]++
+		}
+	}
+
+	graph := historyGraph(paths)
+
+	gotNodes := make(map[string]int)
+	for _, node := range graph.Nodes {
+		gotNodes[node.ElementId]++
+	}
+	if len(wantNodes) != 5 || len(gotNodes) != len(wantNodes) {
+		t.Errorf("got %d distinct nodes, want the 5 of the paths", len(gotNodes))
+	}
+	for elementId := range wantNodes {
+		if gotNodes[elementId] != 1 {
+			t.Errorf("node %s is listed %d times, want once", elementId, gotNodes[elementId])
+		}
+	}
+
+	gotRelationships := make(map[string]int)
+	for _, relation := range graph.Relationships {
+		gotRelationships[relation.ElementId]++
+	}
+	if len(wantRelationships) != 6 || len(gotRelationships) != len(wantRelationships) {
+		t.Errorf("got %d distinct relationships, want the 6 of the paths", len(gotRelationships))
+	}
+	for elementId := range wantRelationships {
+		if gotRelationships[elementId] != 1 {
+			t.Errorf("relationship %s is listed %d times, want once", elementId, gotRelationships[elementId])
+		}
+	}
+}
+
+func TestHistoryGraphWithoutPaths(t *testing.T) {
+	graph := historyGraph(nil)
+	if graph.Nodes == nil || graph.Relationships == nil {
+		t.Errorf("got %+v, want empty lists so they are encoded as []", graph)
+	}
+}
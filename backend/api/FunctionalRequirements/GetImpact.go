@@ -0,0 +1,91 @@
+package functionalrequirements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultImpactDepth is how many `NEEDS` levels are followed when the
+	// `maxDepth` URL query isn't sent.
+	DefaultImpactDepth = 6
+	MaxImpactDepth     = 10
+	// ImpactTimeout is the deadline of the impact analysis.
+	ImpactTimeout = 10 * time.Second
+)
+
+// ImpactResponse lists everything affected if the source becomes unavailable,
+// up to MaxDepth `NEEDS` levels downstream.
+type ImpactResponse struct {
+	MaxDepth  int           `json:"maxDepth"`
+	Source    dto.NodeDTO   `json:"source"`
+	Materials []dto.NodeDTO `json:"materials"`
+	Products  []dto.NodeDTO `json:"products"`
+	Retailers []dto.NodeDTO `json:"retailers"`
+	Consumers []dto.NodeDTO `json:"consumers"`
+}
+
+// NewGetImpactHandler handles `GET /impact`, which walks downstream from the
+// `ProviderId` provider or the `MaterialId` material: the materials and
+// products that need it, directly or through up to `maxDepth` levels of other
+// materials, and the retailers and consumers that bought those products.
+func NewGetImpactHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queries := r.URL.Query()
+		providerId, materialId := queries.Get("ProviderId"), queries.Get("MaterialId")
+		if (providerId == "") == (materialId == "") {
+			apierror.InvalidField(w, r, "", errors.New("exactly one of `ProviderId` and `MaterialId` is required"))
+			return
+		}
+
+		label, id := "Provider", providerId
+		if materialId != "" {
+			label, id = "Material", materialId
+		}
+
+		maxDepth := DefaultImpactDepth
+		if raw := queries.Get("maxDepth"); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value <= 0 || value > MaxImpactDepth {
+				apierror.InvalidField(w, r, "maxDepth", fmt.Errorf("it must be an integer between 1 and %d", MaxImpactDepth))
+				return
+			}
+			maxDepth = value
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), ImpactTimeout)
+		defer cancel()
+
+		log.Info().Str("label", label).Str("id", id).Int("maxDepth", maxDepth).Msg("⏳ Calculando el impacto...")
+		impact, err := db.Impact(ctx, label, id, maxDepth)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error calculando el impacto")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.Timeout(w, r, "The impact analysis didn't finish in time, try a lower `maxDepth`")
+				return
+			}
+			apierror.StoreError(w, r, err, fmt.Sprintf("No %s with id `%s` exists", label, id))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ImpactResponse{
+			MaxDepth:  maxDepth,
+			Source:    dto.Node(impact.Source),
+			Materials: dto.Nodes(impact.Materials),
+			Products:  dto.Nodes(impact.Products),
+			Retailers: dto.Nodes(impact.Retailers),
+			Consumers: dto.Nodes(impact.Consumers),
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package functionalrequirements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultAlternatives is how many routes besides the shortest are returned
+	// when the `k` URL query isn't sent.
+	DefaultAlternatives = 3
+	MaxAlternatives     = 10
+	// DefaultPathDepth is the longest route searched when the `maxDepth` URL
+	// query isn't sent.
+	DefaultPathDepth = 6
+	MaxPathDepth     = 10
+	// PathsTimeout is the deadline of the path search.
+	PathsTimeout = 10 * time.Second
+)
+
+// Route is a path between the requested nodes. Cost is the sum of the weight
+// property of its relationships, or its length when no weight was requested.
+type Route struct {
+	Nodes         []dto.NodeDTO     `json:"nodes"`
+	Relationships []dto.RelationDTO `json:"relationships"`
+	Length        int               `json:"length"`
+	Cost          float64           `json:"cost"`
+}
+
+// PathsResponse has the cheapest route and the next cheapest alternatives,
+// Shortest is null when the nodes aren't connected. With a Weight, only the
+// Candidates routes with the fewest relationships are compared by their cost,
+// so a cheaper route with more relationships may exist.
+type PathsResponse struct {
+	Shortest     *Route  `json:"shortest"`
+	Alternatives []Route `json:"alternatives"`
+	Weight       string  `json:"weight,omitempty"`
+	Candidates   int     `json:"candidates,omitempty"`
+}
+
+// NewGetPathsHandler handles `GET /paths`, which searches the routes between
+// the nodes with element IDs `from` and `to`. `types` restricts the
+// relationships followed to a comma separated list, `weight` names the
+// relationship property added up as the cost, `k` is how many alternatives
+// are returned and `directed` only follows relationships in their direction.
+func NewGetPathsHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queries := r.URL.Query()
+		query := store.PathQuery{From: queries.Get("from"), To: queries.Get("to")}
+		if query.From == "" || query.To == "" {
+			apierror.MissingFields(w, r, "from", "to")
+			return
+		}
+		if query.From == query.To {
+			apierror.InvalidField(w, r, "to", errors.New("it must be a different node than `from`"))
+			return
+		}
+
+		if raw := queries.Get("types"); raw != "" {
+			for _, relType := range strings.Split(raw, ",") {
+				relType = strings.TrimSpace(relType)
+				if err := identifier.Validate(identifier.KindRelationType, relType); err != nil {
+					apierror.InvalidField(w, r, "types", err)
+					return
+				}
+				query.Types = append(query.Types, relType)
+			}
+		}
+
+		if query.Weight = queries.Get("weight"); query.Weight != "" {
+			if err := identifier.Validate(identifier.KindPropertyKey, query.Weight); err != nil {
+				apierror.InvalidField(w, r, "weight", err)
+				return
+			}
+		}
+
+		if raw := queries.Get("directed"); raw != "" {
+			directed, err := strconv.ParseBool(raw)
+			if err != nil {
+				apierror.InvalidField(w, r, "directed", fmt.Errorf("`%s` is not a boolean", raw))
+				return
+			}
+			query.Directed = directed
+		}
+
+		alternatives := DefaultAlternatives
+		if raw := queries.Get("k"); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value < 0 || value > MaxAlternatives {
+				apierror.InvalidField(w, r, "k", fmt.Errorf("it must be an integer between 0 and %d", MaxAlternatives))
+				return
+			}
+			alternatives = value
+		}
+		query.Limit = alternatives + 1
+
+		query.MaxDepth = DefaultPathDepth
+		if raw := queries.Get("maxDepth"); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value <= 0 || value > MaxPathDepth {
+				apierror.InvalidField(w, r, "maxDepth", fmt.Errorf("it must be an integer between 1 and %d", MaxPathDepth))
+				return
+			}
+			query.MaxDepth = value
+		}
+
+		log.Info().Str("from", query.From).Str("to", query.To).Strs("types", query.Types).Msg("⏳ Buscando rutas...")
+		ctx, cancel := context.WithTimeout(r.Context(), PathsTimeout)
+		defer cancel()
+
+		paths, err := db.FindPaths(ctx, query)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error buscando rutas")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.Timeout(w, r, "The path search didn't finish in time, try a lower `maxDepth` or fewer `types`")
+				return
+			}
+			apierror.StoreError(w, r, err, "`from` or `to` doesn't exist")
+			return
+		}
+
+		response := PathsResponse{Alternatives: []Route{}, Weight: query.Weight}
+		if query.Weight != "" {
+			response.Candidates = store.WeightedPathCandidates
+		}
+		for i, path := range paths {
+			route := Route{
+				Nodes:         dto.Nodes(path.Path.Nodes),
+				Relationships: dto.Relations(path.Path.Relationships),
+				Length:        len(path.Path.Relationships),
+				Cost:          path.Cost,
+			}
+			if i == 0 {
+				response.Shortest = &route
+				continue
+			}
+			response.Alternatives = append(response.Alternatives, route)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
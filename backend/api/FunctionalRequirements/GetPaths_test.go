@@ -0,0 +1,81 @@
+package functionalrequirements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+	"github.com/rs/zerolog"
+)
+
+// elementId returns the element ID of the node with label and id.
+func elementId(t *testing.T, db store.GraphStore, label string, id string) string {
+	t.Helper()
+	nodes, err := db.FindNodes(context.Background(), store.Object{Category: label, Properties: map[string]any{"id": id}}, 1)
+	if err != nil || len(nodes) == 0 {
+		t.Fatalf("%s %s not found: %v", label, id, err)
+	}
+	return nodes[0].ElementId
+}
+
+func TestPathsResponse(t *testing.T) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	db := memstore.New()
+	if err := db.Seed(strings.NewReader(historyFixture)); err != nil {
+		t.Fatal(err)
+	}
+	from, to := elementId(t, db, "Provider", "PR1"), elementId(t, db, "Product", "P1")
+
+	tests := []struct {
+		weight     string
+		candidates int
+	}{
+		{"", 0},
+		{"quantity", store.WeightedPathCandidates},
+	}
+	for _, test := range tests {
+		query := url.Values{"from": {from}, "to": {to}, "weight": {test.weight}}
+		w := httptest.NewRecorder()
+		NewGetPathsHandler(db)(w, httptest.NewRequest(http.MethodGet, "/paths?"+query.Encode(), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("weight %q: answered %d: %s", test.weight, w.Code, w.Body)
+		}
+
+		var response PathsResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		if response.Shortest == nil || response.Shortest.Length != 2 {
+			t.Errorf("weight %q: got shortest %+v, want a route of 2 relationships", test.weight, response.Shortest)
+		}
+		if response.Candidates != test.candidates {
+			t.Errorf("weight %q: got %d candidates, want %d", test.weight, response.Candidates, test.candidates)
+		}
+	}
+}
+
+func TestFindPathsStopsWhenCanceled(t *testing.T) {
+	db := memstore.New()
+	if err := db.Seed(strings.NewReader(historyFixture)); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	query := store.PathQuery{
+		From:     elementId(t, db, "Provider", "PR1"),
+		To:       elementId(t, db, "Product", "P2"),
+		MaxDepth: MaxPathDepth,
+		Limit:    1,
+	}
+	if _, err := db.FindPaths(ctx, query); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
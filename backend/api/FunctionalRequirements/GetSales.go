@@ -0,0 +1,213 @@
+package functionalrequirements
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+// SalesInterval is the length of the buckets of a sales series.
+type SalesInterval string
+
+const (
+	SalesDaily   SalesInterval = "day"
+	SalesWeekly  SalesInterval = "week"
+	SalesMonthly SalesInterval = "month"
+)
+
+// MaxSalesBuckets is the most buckets a sales series can have.
+const MaxSalesBuckets = 1000
+
+// SalesTimeout is the deadline of the sales query.
+const SalesTimeout = 10 * time.Second
+
+// SalesSeries has the purchases of a group in every bucket, in the same order
+// as SalesResponse.Buckets.
+type SalesSeries struct {
+	Id     string  `json:"id,omitempty"`
+	Name   string  `json:"name"`
+	Values []int64 `json:"values"`
+	Total  int64   `json:"total"`
+}
+
+// SalesResponse has a series for every group, they're sorted by their total.
+// Buckets are the first days of every interval between the first and the last
+// purchase, or the requested window.
+type SalesResponse struct {
+	Interval SalesInterval    `json:"interval"`
+	GroupBy  store.SalesGroup `json:"groupBy,omitempty"`
+	Since    string           `json:"since,omitempty"`
+	Until    string           `json:"until,omitempty"`
+	Buckets  []string         `json:"buckets"`
+	Series   []SalesSeries    `json:"series"`
+}
+
+// NewGetSalesHandler handles `GET /statistics/sales`, which counts the
+// `BUYS_FROM_RETAILER` relationships by their `date`. `interval` is `day`,
+// `week` or `month`, the default, `groupBy` splits the purchases by
+// `product`, `retailer` or `provider`, and `since` and `until` bound the
+// dates counted. Weeks start on monday.
+func NewGetSalesHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queries := r.URL.Query()
+
+		interval := SalesInterval(queries.Get("interval"))
+		switch interval {
+		case "":
+			interval = SalesMonthly
+		case SalesDaily, SalesWeekly, SalesMonthly:
+		default:
+			apierror.InvalidField(w, r, "interval", fmt.Errorf("`%s` isn't one of `day`, `week` or `month`", interval))
+			return
+		}
+
+		var query store.SalesQuery
+		query.GroupBy = store.SalesGroup(queries.Get("groupBy"))
+		switch query.GroupBy {
+		case "", store.SalesByProduct, store.SalesByRetailer, store.SalesByProvider:
+		default:
+			apierror.InvalidField(w, r, "groupBy", fmt.Errorf("`%s` isn't one of `product`, `retailer` or `provider`", query.GroupBy))
+			return
+		}
+
+		var ok bool
+		if query.Since, query.Until, ok = readDateWindow(w, r); !ok {
+			return
+		}
+
+		log.Info().Str("interval", string(interval)).Str("groupBy", string(query.GroupBy)).Msg("⏳ Calculando las ventas...")
+		ctx, cancel := context.WithTimeout(r.Context(), SalesTimeout)
+		defer cancel()
+
+		sales, err := db.DailySales(ctx, query)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error calculando las ventas")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.Timeout(w, r, "The sales query didn't finish in time, try a shorter window")
+				return
+			}
+			apierror.DBError(w, r, err)
+			return
+		}
+
+		response, err := salesSeries(sales, query, interval)
+		if err != nil {
+			apierror.InvalidField(w, r, "interval", err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// salesSeries adds up the daily sales into the buckets of every group.
+func salesSeries(sales []store.DailySales, query store.SalesQuery, interval SalesInterval) (SalesResponse, error) {
+	response := SalesResponse{
+		Interval: interval,
+		GroupBy:  query.GroupBy,
+		Since:    query.Since,
+		Until:    query.Until,
+		Buckets:  []string{},
+		Series:   []SalesSeries{},
+	}
+
+	// Days that aren't dates are skipped, Neo4j may return any string.
+	days := make([]time.Time, len(sales))
+	var first, last time.Time
+	for i, daily := range sales {
+		day, err := time.Parse(store.DateLayout, daily.Day)
+		if err != nil {
+			continue
+		}
+		days[i] = day
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if last.IsZero() || day.After(last) {
+			last = day
+		}
+	}
+	if query.Since != "" {
+		first, _ = time.Parse(store.DateLayout, query.Since)
+	}
+	if query.Until != "" {
+		last, _ = time.Parse(store.DateLayout, query.Until)
+	}
+	if first.IsZero() || last.IsZero() {
+		return response, nil
+	}
+
+	positions := make(map[time.Time]int)
+	for bucket := bucketStart(first, interval); !bucket.After(last); bucket = nextBucket(bucket, interval) {
+		if len(response.Buckets) == MaxSalesBuckets {
+			return response, fmt.Errorf("the dates span more than %d buckets, use a longer interval or a shorter window", MaxSalesBuckets)
+		}
+		positions[bucket] = len(response.Buckets)
+		response.Buckets = append(response.Buckets, bucket.Format(store.DateLayout))
+	}
+
+	series := make(map[string]*SalesSeries)
+	var order []string
+	for i, daily := range sales {
+		if days[i].IsZero() {
+			continue
+		}
+		position, found := positions[bucketStart(days[i], interval)]
+		if !found {
+			continue
+		}
+
+		key := daily.GroupId + "\x00" + daily.GroupName
+		s, found := series[key]
+		if !found {
+			s = &SalesSeries{Id: daily.GroupId, Name: daily.GroupName, Values: make([]int64, len(response.Buckets))}
+			if query.GroupBy == "" {
+				s.Name = "Total"
+			}
+			series[key] = s
+			order = append(order, key)
+		}
+		s.Values[position] += daily.Sales
+		s.Total += daily.Sales
+	}
+
+	for _, key := range order {
+		response.Series = append(response.Series, *series[key])
+	}
+	slices.SortStableFunc(response.Series, func(a, b SalesSeries) int {
+		return cmp.Compare(b.Total, a.Total)
+	})
+	return response, nil
+}
+
+// bucketStart returns the first day of the interval that has day.
+func bucketStart(day time.Time, interval SalesInterval) time.Time {
+	switch interval {
+	case SalesWeekly:
+		// time.Sunday is 0, so sunday goes back 6 days to monday.
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case SalesMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextBucket(bucket time.Time, interval SalesInterval) time.Time {
+	switch interval {
+	case SalesWeekly:
+		return bucket.AddDate(0, 0, 7)
+	case SalesMonthly:
+		return bucket.AddDate(0, 1, 0)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
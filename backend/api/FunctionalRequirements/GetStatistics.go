@@ -3,10 +3,15 @@ package functionalrequirements
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -16,104 +21,175 @@ func enableCors(w *http.ResponseWriter) {
 	(*w).Header().Set("Access-Control-Allow-Headers", "Content-Type")
 }
 
-func GetStatisticsHandler(db *neo4j.DriverWithContext) http.HandlerFunc {
+const (
+	// MaxStatisticsLimit is the most entries a ranking can be asked to keep.
+	MaxStatisticsLimit = 100
+	// StatisticsTimeout is the deadline shared by the queries of every ranking.
+	StatisticsTimeout = 10 * time.Second
+)
+
+// SectionError tells why a ranking couldn't be computed.
+type SectionError struct {
+	Code    apierror.Code `json:"code"`
+	Message string        `json:"message"`
+}
+
+// StatisticsResponse has the rankings and the filters that were applied to
+// them. Errors maps the key of every ranking that failed to the reason, the
+// ranking itself is left empty.
+type StatisticsResponse struct {
+	store.Statistics
+	Filters store.StatisticsFilter  `json:"filters"`
+	Errors  map[string]SectionError `json:"errors,omitempty"`
+}
+
+// statisticsSection computes one of the rankings.
+type statisticsSection struct {
+	key string
+	run func(ctx context.Context) error
+}
+
+// GetStatisticsHandler handles `GET /statistics`. `limit` sets the length of
+// every ranking, `category` only counts the products of a category, `country`
+// only counts the providers of a country and `since` and `until` bound the
+// dates of the ratings and purchases counted.
+//
+// The rankings are computed concurrently under a shared deadline. If some of
+// them fail the others are still returned, along with the errors.
+func GetStatisticsHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		enableCors(&w)
 		if db == nil {
-			http.Error(w, "500 Internal Server Error - DB connection is nil", http.StatusInternalServerError)
+			apierror.Internal(w, r, "The database connection is nil")
 			return
 		}
 
-		ctx := context.Background()
-
-		queryTopProducts := `
-			MATCH (c:Consumer)-[r:RATES]->(p:Product)
-			RETURN p.name AS name, AVG(r.rating) AS average_rating
-			ORDER BY average_rating DESC
-			LIMIT 3
-		`
-
-		queryTopProviders := `
-			MATCH (p:Provider)<-[r:PREFERS]-(c:Retailer)
-			RETURN p.name AS name, COUNT(r) AS popularity
-			ORDER BY popularity DESC
-			LIMIT 5
-		`
-
-		queryTopPurchasedProducts := `
-			MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer),
-			      (p:Product {id: r.productId})  
-			RETURN p.name AS product_name, r.productId AS product_id, COUNT(r) AS purchases
-			ORDER BY purchases DESC
-			LIMIT 10
-		`
-
-		log.Info().Msg("Ejecutando consultas de estadísticas...")
-
-		// 🏆 Ejecutar top productos
-		resultTopProducts, err := neo4j.ExecuteQuery(ctx, *db, queryTopProducts, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
-		if err != nil {
-			http.Error(w, fmt.Sprintf("500 Internal Server Error - Error en top productos: %s", err.Error()), http.StatusInternalServerError)
+		filter, ok := readStatisticsFilter(w, r)
+		if !ok {
 			return
 		}
 
-		var topProducts []map[string]any
-		for _, record := range resultTopProducts.Records {
-			name, _ := record.Get("name")
-			averageRating, _ := record.Get("average_rating")
+		ctx, cancel := context.WithTimeout(r.Context(), StatisticsTimeout)
+		defer cancel()
+
+		// Every section writes a different field, so they don't need a lock.
+		response := StatisticsResponse{
+			Statistics: store.Statistics{
+				TopProducts:          []store.ProductRating{},
+				TopProviders:         []store.ProviderPopularity{},
+				TopPurchasedProducts: []store.PurchasedProduct{},
+			},
+			Filters: filter,
+		}
+		sections := []statisticsSection{
+			{"top_products", func(ctx context.Context) error {
+				ranking, err := db.TopProducts(ctx, filter)
+				if err == nil {
+					response.TopProducts = ranking
+				}
+				return err
+			}},
+			{"top_providers", func(ctx context.Context) error {
+				ranking, err := db.TopProviders(ctx, filter)
+				if err == nil {
+					response.TopProviders = ranking
+				}
+				return err
+			}},
+			{"top_purchased_products", func(ctx context.Context) error {
+				ranking, err := db.TopPurchasedProducts(ctx, filter)
+				if err == nil {
+					response.TopPurchasedProducts = ranking
+				}
+				return err
+			}},
+		}
 
-			topProducts = append(topProducts, map[string]any{
-				"name":           name,
-				"average_rating": averageRating,
-			})
+		log.Info().Interface("filters", filter).Msg("Ejecutando consultas de estadísticas...")
+		errs := make([]error, len(sections))
+		var wg sync.WaitGroup
+		for i, section := range sections {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = section.run(ctx)
+			}()
+		}
+		wg.Wait()
+
+		for i, err := range errs {
+			if err == nil {
+				continue
+			}
+			log.Error().Err(err).Str("section", sections[i].key).Msg("❌ Error en una consulta de estadísticas")
+			if response.Errors == nil {
+				response.Errors = make(map[string]SectionError)
+			}
+			response.Errors[sections[i].key] = sectionError(err)
 		}
 
-		// 🔝 Ejecutar top proveedores
-		resultTopProviders, err := neo4j.ExecuteQuery(ctx, *db, queryTopProviders, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
-		if err != nil {
-			http.Error(w, fmt.Sprintf("500 Internal Server Error - Error en top proveedores: %s", err.Error()), http.StatusInternalServerError)
+		if len(response.Errors) == len(sections) {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.Timeout(w, r, "The statistics queries didn't finish in time")
+				return
+			}
+			apierror.DBError(w, r, errors.Join(errs...))
 			return
 		}
 
-		var topProviders []map[string]any
-		for _, record := range resultTopProviders.Records {
-			name, _ := record.Get("name")
-			popularity, _ := record.Get("popularity")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	}
+}
 
-			topProviders = append(topProviders, map[string]any{
-				"name":       name,
-				"popularity": popularity,
-			})
+// readStatisticsFilter reads the URL queries of `GET /statistics`. If they're
+// invalid an error response is sent and ok is false.
+func readStatisticsFilter(w http.ResponseWriter, r *http.Request) (filter store.StatisticsFilter, ok bool) {
+	queries := r.URL.Query()
+	filter.Category = queries.Get("category")
+	filter.Country = queries.Get("country")
+
+	if raw := queries.Get("limit"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 || value > MaxStatisticsLimit {
+			apierror.InvalidField(w, r, "limit", fmt.Errorf("it must be an integer between 1 and %d", MaxStatisticsLimit))
+			return filter, false
 		}
+		filter.Limit = value
+	}
 
-		// 🛍️ Ejecutar top productos comprados
-		resultTopPurchasedProducts, err := neo4j.ExecuteQuery(ctx, *db, queryTopPurchasedProducts, nil, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
-		if err != nil {
-			http.Error(w, fmt.Sprintf("500 Internal Server Error - Error en top productos comprados: %s", err.Error()), http.StatusInternalServerError)
-			return
-		}
+	filter.Since, filter.Until, ok = readDateWindow(w, r)
+	return filter, ok
+}
 
-		var topPurchasedProducts []map[string]any
-		for _, record := range resultTopPurchasedProducts.Records {
-			productName, _ := record.Get("product_name")
-			productID, _ := record.Get("product_id")
-			purchases, _ := record.Get("purchases")
-
-			topPurchasedProducts = append(topPurchasedProducts, map[string]any{
-				"product_name": productName,
-				"product_id":   productID,
-				"purchases":    purchases,
-			})
+// readDateWindow reads the `since` and `until` URL queries. If they're invalid
+// an error response is sent and ok is false.
+func readDateWindow(w http.ResponseWriter, r *http.Request) (since string, until string, ok bool) {
+	queries := r.URL.Query()
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{{"since", &since}, {"until", &until}} {
+		*field.value = queries.Get(field.name)
+		if *field.value == "" {
+			continue
 		}
-
-		response := map[string]any{
-			"top_products":           topProducts,
-			"top_providers":          topProviders,
-			"top_purchased_products": topPurchasedProducts,
+		if _, err := time.Parse(store.DateLayout, *field.value); err != nil {
+			apierror.InvalidField(w, r, field.name, fmt.Errorf("`%s` is not a YYYY-MM-DD date", *field.value))
+			return since, until, false
 		}
+	}
+	if since != "" && until != "" && since > until {
+		apierror.InvalidField(w, r, "until", errors.New("it must not be before `since`"))
+		return since, until, false
+	}
+	return since, until, true
+}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response)
+func sectionError(err error) SectionError {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return SectionError{Code: apierror.CodeTimeout, Message: "The query didn't finish in time"}
 	}
+	return SectionError{Code: apierror.CodeDBError, Message: err.Error()}
 }
@@ -0,0 +1,126 @@
+package functionalrequirements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+// RisksTimeout is the deadline of the supply risk analysis.
+const RisksTimeout = 10 * time.Second
+
+// SingleSourcedMaterial is a material produced by only one provider, Products
+// are the products that need it directly or through other materials.
+type SingleSourcedMaterial struct {
+	Material dto.NodeDTO   `json:"material"`
+	Provider dto.NodeDTO   `json:"provider"`
+	Products []dto.NodeDTO `json:"products"`
+}
+
+// SingleProviderProduct is a product whose materials all come from the same
+// provider, so losing it stops the product. A product needing a material
+// nobody produces isn't listed, it can't be made whatever the provider does.
+type SingleProviderProduct struct {
+	Product   dto.NodeDTO   `json:"product"`
+	Provider  dto.NodeDTO   `json:"provider"`
+	Materials []dto.NodeDTO `json:"materials"`
+}
+
+// SupplyRisksResponse lists the single points of failure of the supply network.
+type SupplyRisksResponse struct {
+	SingleSourcedMaterials []SingleSourcedMaterial `json:"singleSourcedMaterials"`
+	SingleProviderProducts []SingleProviderProduct `json:"singleProviderProducts"`
+}
+
+// NewGetSupplyRisksHandler handles `GET /risks`, which finds the materials
+// with exactly one producing provider and the products whose every material,
+// direct or nested, is produced by the same single provider and no other.
+func NewGetSupplyRisksHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log.Info().Msg("⏳ Buscando puntos únicos de falla...")
+		ctx, cancel := context.WithTimeout(r.Context(), RisksTimeout)
+		defer cancel()
+
+		network, err := db.SupplyNetwork(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error buscando puntos únicos de falla")
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apierror.Timeout(w, r, "The supply risk analysis didn't finish in time")
+				return
+			}
+			apierror.DBError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(supplyRisks(network))
+	}
+}
+
+func supplyRisks(network store.SupplyNetwork) SupplyRisksResponse {
+	response := SupplyRisksResponse{
+		SingleSourcedMaterials: []SingleSourcedMaterial{},
+		SingleProviderProducts: []SingleProviderProduct{},
+	}
+
+	materials := make(map[string]store.Node, len(network.Materials))
+	for _, material := range network.Materials {
+		materials[material.ElementId] = material
+	}
+
+	dependents := make(map[string][]dto.NodeDTO)
+	for _, product := range network.Products {
+		var needed []dto.NodeDTO
+		providers := make(map[string]store.Node)
+		unsourced := false
+		for _, materialId := range network.Needs[product.ElementId] {
+			material, found := materials[materialId]
+			if !found {
+				continue
+			}
+			dependents[materialId] = append(dependents[materialId], dto.Node(product))
+			needed = append(needed, dto.Node(material))
+			if len(network.Providers[materialId]) == 0 {
+				unsourced = true
+			}
+			for _, provider := range network.Providers[materialId] {
+				providers[provider.ElementId] = provider
+			}
+		}
+
+		if unsourced || len(providers) != 1 {
+			continue
+		}
+		for _, provider := range providers {
+			response.SingleProviderProducts = append(response.SingleProviderProducts, SingleProviderProduct{
+				Product:   dto.Node(product),
+				Provider:  dto.Node(provider),
+				Materials: needed,
+			})
+		}
+	}
+
+	for _, material := range network.Materials {
+		providers := network.Providers[material.ElementId]
+		if len(providers) != 1 {
+			continue
+		}
+		products := dependents[material.ElementId]
+		if products == nil {
+			products = []dto.NodeDTO{}
+		}
+		response.SingleSourcedMaterials = append(response.SingleSourcedMaterials, SingleSourcedMaterial{
+			Material: dto.Node(material),
+			Provider: dto.Node(providers[0]),
+			Products: products,
+		})
+	}
+	return response
+}
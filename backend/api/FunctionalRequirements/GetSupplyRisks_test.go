@@ -0,0 +1,132 @@
+package functionalrequirements
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+)
+
+// risksFixture covers nested materials (P1 needs M2 through M1), a material
+// with two providers (M3), a material nobody produces (M4) and a product
+// needed by another product (P4 needs P5), which isn't followed.
+const risksFixture = `{
+	"nodes": [
+		{"key": "pr1", "labels": ["Provider"], "properties": {"id": "PR1"}},
+		{"key": "pr2", "labels": ["Provider"], "properties": {"id": "PR2"}},
+		{"key": "m1", "labels": ["Material"], "properties": {"id": "M1"}},
+		{"key": "m2", "labels": ["Material"], "properties": {"id": "M2"}},
+		{"key": "m3", "labels": ["Material"], "properties": {"id": "M3"}},
+		{"key": "m4", "labels": ["Material"], "properties": {"id": "M4"}},
+		{"key": "m5", "labels": ["Material"], "properties": {"id": "M5"}},
+		{"key": "p1", "labels": ["Product"], "properties": {"id": "P1"}},
+		{"key": "p2", "labels": ["Product"], "properties": {"id": "P2"}},
+		{"key": "p3", "labels": ["Product"], "properties": {"id": "P3"}},
+		{"key": "p4", "labels": ["Product"], "properties": {"id": "P4"}},
+		{"key": "p5", "labels": ["Product"], "properties": {"id": "P5"}}
+	],
+	"relations": [
+		{"type": "PRODUCES", "from": "pr1", "to": "m1", "properties": {}},
+		{"type": "PRODUCES", "from": "pr1", "to": "m2", "properties": {}},
+		{"type": "PRODUCES", "from": "pr1", "to": "m3", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m3", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m5", "properties": {}},
+		{"type": "NEEDS", "from": "p1", "to": "m1", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m1", "to": "m2", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p2", "to": "m1", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p2", "to": "m3", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p3", "to": "m2", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p3", "to": "m4", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p4", "to": "p5", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p5", "to": "m5", "properties": {"quantity": 1}}
+	]
+}`
+
+func TestSupplyRisks(t *testing.T) {
+	db := memstore.New()
+	if err := db.Seed(strings.NewReader(risksFixture)); err != nil {
+		t.Fatal(err)
+	}
+	network, err := db.SupplyNetwork(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	risks := supplyRisks(network)
+
+	materials := make(map[string]SingleSourcedMaterial)
+	for _, risk := range risks.SingleSourcedMaterials {
+		materials[id(risk.Material)] = risk
+	}
+	wantMaterials := map[string]struct {
+		provider string
+		products []string
+	}{
+		"M1": {"PR1", []string{"P1", "P2"}},
+		"M2": {"PR1", []string{"P1", "P2", "P3"}},
+		"M5": {"PR2", []string{"P5"}},
+	}
+	if len(materials) != len(wantMaterials) {
+		t.Errorf("got %d single sourced materials, want %d", len(materials), len(wantMaterials))
+	}
+	for materialId, want := range wantMaterials {
+		got, found := materials[materialId]
+		if !found {
+			t.Errorf("material %s isn't reported as single sourced", materialId)
+			continue
+		}
+		if id(got.Provider) != want.provider {
+			t.Errorf("material %s has provider %s, want %s", materialId, id(got.Provider), want.provider)
+		}
+		if products := ids(got.Products); !slices.Equal(products, want.products) {
+			t.Errorf("material %s is needed by %v, want %v", materialId, products, want.products)
+		}
+	}
+
+	products := make(map[string]SingleProviderProduct)
+	for _, risk := range risks.SingleProviderProducts {
+		products[id(risk.Product)] = risk
+	}
+	wantProducts := map[string]struct {
+		provider  string
+		materials []string
+	}{
+		"P1": {"PR1", []string{"M1", "M2"}},
+		"P5": {"PR2", []string{"M5"}},
+	}
+	if len(products) != len(wantProducts) {
+		t.Errorf("got %d single provider products, want %d", len(products), len(wantProducts))
+	}
+	for productId, want := range wantProducts {
+		got, found := products[productId]
+		if !found {
+			t.Errorf("product %s isn't reported as single provider", productId)
+			continue
+		}
+		if id(got.Provider) != want.provider {
+			t.Errorf("product %s has provider %s, want %s", productId, id(got.Provider), want.provider)
+		}
+		if materials := ids(got.Materials); !slices.Equal(materials, want.materials) {
+			t.Errorf("product %s needs %v, want %v", productId, materials, want.materials)
+		}
+	}
+}
+
+func id(node dto.NodeDTO) string {
+	value, _ := node.Properties["id"].(string)
+	return value
+}
+
+// ids returns the sorted ids of nodes, so the order they were found in
+// doesn't matter.
+func ids(nodes []dto.NodeDTO) []string {
+	values := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		values = append(values, id(node))
+	}
+	slices.Sort(values)
+	return values
+}
@@ -0,0 +1,307 @@
+// Package importer implements `POST /import/nodes` and `POST /import/relations`,
+// which stream CSV or JSON Lines bodies into the graph.
+//
+// The format is chosen with the `format` URL query (`csv` or `ndjson`), or else
+// with the Content-Type header. CSV columns may declare their type in the
+// header as `name:type`, where type is `string` (the default), `int`, `float`
+// or `bool`:
+//
+//	id,name,price:float,stock:int
+//	P10,Lámpara,120.5,30
+//
+// JSON Lines rows are flat objects whose values are stored as they come.
+// Empty CSV cells and JSON nulls are skipped.
+//
+// Rows are written `chunkSize` at a time with UNWIND. A row that can't be
+// imported doesn't stop the others, it's listed in the report by its line.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+)
+
+const (
+	DefaultChunkSize = 500
+	MaxChunkSize     = 5000
+	// MaxReportedErrors is the most row errors a report lists.
+	MaxReportedErrors = 100
+	// MaxLineSize is the longest JSON Lines row accepted, in bytes.
+	MaxLineSize = 1 << 20
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// options are the URL queries shared by both endpoints.
+type options struct {
+	format    Format
+	chunkSize int
+}
+
+func readOptions(r *http.Request) (options, error) {
+	url_queries := r.URL.Query()
+	opts := options{format: Format(url_queries.Get("format")), chunkSize: DefaultChunkSize}
+
+	if opts.format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			opts.format = FormatCSV
+		case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+			opts.format = FormatNDJSON
+		}
+	}
+	if opts.format != FormatCSV && opts.format != FormatNDJSON {
+		return opts, &utils.FieldError{Field: "format", Err: errors.New("it must be `csv` or `ndjson`, or be given by the Content-Type")}
+	}
+
+	if chunkSize := url_queries.Get("chunkSize"); chunkSize != "" {
+		value, err := strconv.Atoi(chunkSize)
+		if err != nil || value <= 0 || value > MaxChunkSize {
+			return opts, &utils.FieldError{Field: "chunkSize", Err: fmt.Errorf("it must be an integer between 1 and %d", MaxChunkSize)}
+		}
+		opts.chunkSize = value
+	}
+	return opts, nil
+}
+
+// row is a row of the body, Err is set when it couldn't be parsed.
+type row struct {
+	Line   int
+	Values map[string]any
+	Err    error
+}
+
+type rowReader interface {
+	// next returns io.EOF after the last row. Any other error means the rest
+	// of the body can't be read, the returned row tells where it happened.
+	next() (row, error)
+}
+
+func newRowReader(format Format, body io.Reader) (rowReader, error) {
+	if format == FormatCSV {
+		return newCSVReader(body)
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	return &ndjsonReader{scanner: scanner}, nil
+}
+
+type column struct {
+	name string
+	kind string
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns []column
+}
+
+func newCSVReader(body io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(body)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, &utils.FieldError{Field: "body", Err: errors.New("the CSV has no header")}
+	}
+	if err != nil {
+		return nil, &utils.FieldError{Field: "body", Err: err}
+	}
+
+	columns := make([]column, 0, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, title := range header {
+		if i == 0 {
+			// Spreadsheets often save CSVs with a byte order mark.
+			title = strings.TrimPrefix(title, "\uFEFF")
+		}
+		name, kind, _ := strings.Cut(strings.TrimSpace(title), ":")
+		if kind == "" {
+			kind = "string"
+		}
+
+		if err := identifier.Validate(identifier.KindPropertyKey, name); err != nil {
+			return nil, &utils.FieldError{Field: "body", Err: fmt.Errorf("column %d: %w", i+1, err)}
+		}
+		if _, err := coerce(kind, ""); errors.Is(err, errUnknownType) {
+			return nil, &utils.FieldError{Field: "body", Err: fmt.Errorf("column `%s`: unknown type `%s`", name, kind)}
+		}
+		if seen[name] {
+			return nil, &utils.FieldError{Field: "body", Err: fmt.Errorf("column `%s` is repeated", name)}
+		}
+		seen[name] = true
+		columns = append(columns, column{name: name, kind: kind})
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) next() (row, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return row{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return row{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		line, _ := c.reader.FieldPos(0)
+		return row{Line: line}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	values := make(map[string]any, len(c.columns))
+	for i, column := range c.columns {
+		if record[i] == "" {
+			continue
+		}
+		value, err := coerce(column.kind, record[i])
+		if err != nil {
+			return row{Line: line, Err: fmt.Errorf("column `%s`: %w", column.name, err)}, nil
+		}
+		values[column.name] = value
+	}
+	return row{Line: line, Values: values}, nil
+}
+
+var errUnknownType = errors.New("unknown type")
+
+// coerce converts a CSV cell into a value of the column type.
+func coerce(kind string, cell string) (any, error) {
+	var value any
+	var err error
+	switch kind {
+	case "string":
+		return cell, nil
+	case "int":
+		value, err = strconv.ParseInt(strings.TrimSpace(cell), 10, 64)
+	case "float":
+		value, err = strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	case "bool":
+		value, err = strconv.ParseBool(strings.TrimSpace(cell))
+	default:
+		return nil, errUnknownType
+	}
+	if err != nil {
+		return nil, fmt.Errorf("`%s` is not a valid %s", cell, kind)
+	}
+	return value, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (n *ndjsonReader) next() (row, error) {
+	for n.scanner.Scan() {
+		n.line++
+		text := bytes.TrimSpace(n.scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.UseNumber()
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			return row{Line: n.line, Err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+
+		values := make(map[string]any, len(object))
+		for key, value := range object {
+			if value == nil {
+				continue
+			}
+			if err := identifier.Validate(identifier.KindPropertyKey, key); err != nil {
+				return row{Line: n.line, Err: err}, nil
+			}
+			converted, err := fromJSON(value)
+			if err != nil {
+				return row{Line: n.line, Err: fmt.Errorf("`%s`: %w", key, err)}, nil
+			}
+			values[key] = converted
+		}
+		return row{Line: n.line, Values: values}, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return row{Line: n.line + 1}, err
+	}
+	return row{}, io.EOF
+}
+
+// fromJSON converts a decoded JSON value into a property value, integers are
+// kept as integers. Neo4j can't store maps so objects are rejected.
+func fromJSON(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if integer, err := v.Int64(); err == nil {
+			return integer, nil
+		}
+		return v.Float64()
+	case map[string]any:
+		return nil, errors.New("objects can't be stored as properties")
+	case []any:
+		list := make([]any, 0, len(v))
+		for _, item := range v {
+			if _, isList := item.([]any); isList {
+				return nil, errors.New("nested lists can't be stored as properties")
+			}
+			converted, err := fromJSON(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, converted)
+		}
+		return list, nil
+	}
+	return value, nil
+}
+
+// report accumulates the outcome of an import.
+type report struct {
+	dto.ImportReportDTO
+}
+
+func newReport() *report {
+	return &report{dto.ImportReportDTO{Errors: []dto.ImportErrorDTO{}}}
+}
+
+func (r *report) fail(line int, err error) {
+	r.Failed++
+	if len(r.Errors) >= MaxReportedErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, dto.ImportErrorDTO{Line: line, Reason: err.Error()})
+}
+
+// result returns the report with its errors sorted by line, rows that failed
+// while writing a chunk are reported after the ones that failed parsing.
+func (r *report) result() dto.ImportReportDTO {
+	slices.SortStableFunc(r.Errors, func(a, b dto.ImportErrorDTO) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+	return r.ImportReportDTO
+}
@@ -0,0 +1,147 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+type nodeRow struct {
+	label string
+	row   store.ImportRow
+}
+
+// NewImportNodesHandler handles `POST /import/nodes`. Every row becomes a node
+// labeled with the `NodeType` URL query, or with the value of the column
+// named by the `LabelColumn` URL query when the row has one. Rows that break
+// the schema registry are reported as failed.
+func NewImportNodesHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		opts, err := readOptions(r)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		url_queries := r.URL.Query()
+		nodeType := url_queries.Get("NodeType")
+		labelColumn := url_queries.Get("LabelColumn")
+		if nodeType == "" && labelColumn == "" {
+			apierror.MissingFields(w, r, "NodeType")
+			return
+		}
+		if nodeType != "" {
+			if err := identifier.Validate(identifier.KindLabel, nodeType); err != nil {
+				apierror.InvalidField(w, r, "NodeType", err)
+				return
+			}
+		}
+
+		reader, err := newRowReader(opts.format, r.Body)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		log.Info().Str("format", string(opts.format)).Int("chunkSize", opts.chunkSize).Msg("⏳ Importando nodos...")
+		report := newReport()
+		chunk := make([]nodeRow, 0, opts.chunkSize)
+		for {
+			row, err := reader.next()
+			if err == io.EOF {
+				break
+			}
+			report.Rows++
+			if err != nil {
+				report.fail(row.Line, fmt.Errorf("the rest of the body couldn't be read: %w", err))
+				break
+			}
+			if row.Err != nil {
+				report.fail(row.Line, row.Err)
+				continue
+			}
+
+			label, err := rowLabel(row, nodeType, labelColumn)
+			if err != nil {
+				report.fail(row.Line, err)
+				continue
+			}
+			if err := registry.CheckNode([]string{label}, row.Values); err != nil {
+				report.fail(row.Line, err)
+				continue
+			}
+
+			chunk = append(chunk, nodeRow{label: label, row: store.ImportRow{Line: row.Line, Properties: row.Values}})
+			if len(chunk) == opts.chunkSize {
+				importNodes(ctx, db, report, chunk)
+				chunk = chunk[:0]
+			}
+		}
+		importNodes(ctx, db, report, chunk)
+
+		log.Info().Int("imported", report.Imported).Int("failed", report.Failed).Msg("✅ Importación de nodos terminada")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report.result())
+	}
+}
+
+// rowLabel returns the label of the node created by row, the label column
+// isn't stored as a property.
+func rowLabel(row row, nodeType string, labelColumn string) (string, error) {
+	if labelColumn == "" {
+		return nodeType, nil
+	}
+
+	value, found := row.Values[labelColumn]
+	delete(row.Values, labelColumn)
+	if !found {
+		if nodeType == "" {
+			return "", fmt.Errorf("`%s` is required", labelColumn)
+		}
+		return nodeType, nil
+	}
+
+	label, isString := value.(string)
+	if !isString {
+		return "", fmt.Errorf("`%s` must be a string", labelColumn)
+	}
+	if err := identifier.Validate(identifier.KindLabel, label); err != nil {
+		return "", fmt.Errorf("`%s`: %w", labelColumn, err)
+	}
+	return label, nil
+}
+
+// importNodes writes a chunk, with a query for each label in it.
+func importNodes(ctx context.Context, db store.GraphStore, report *report, chunk []nodeRow) {
+	var labels []string
+	groups := make(map[string][]store.ImportRow)
+	for _, node := range chunk {
+		if _, found := groups[node.label]; !found {
+			labels = append(labels, node.label)
+		}
+		groups[node.label] = append(groups[node.label], node.row)
+	}
+
+	for _, label := range labels {
+		rows := groups[label]
+		created, err := db.ImportNodes(ctx, label, rows)
+		if err != nil {
+			log.Error().Err(err).Str("label", label).Int("rows", len(rows)).Msg("❌ Error importando nodos")
+			for _, row := range rows {
+				report.fail(row.Line, fmt.Errorf("the chunk couldn't be written: %w", err))
+			}
+			continue
+		}
+		report.Imported += created
+	}
+}
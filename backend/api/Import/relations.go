@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// FromColumn holds the key of the origin node of every relationship row.
+	FromColumn = "from"
+	// ToColumn holds the key of the destination node of every relationship row.
+	ToColumn = "to"
+)
+
+// NewImportRelationsHandler handles `POST /import/relations`. Every row links
+// the `OriginType` node whose `OriginKey` property equals its `from` column to
+// the `DestinationType` node whose `DestinationKey` property equals its `to`
+// column with a `RelationType` relationship. The other columns are stored as
+// properties of the relationship. Rows that break the schema registry are
+// reported as failed.
+func NewImportRelationsHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		opts, err := readOptions(r)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		spec, ok := readRelationImport(w, r)
+		if !ok {
+			return
+		}
+
+		reader, err := newRowReader(opts.format, r.Body)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
+
+		log.Info().Str("format", string(opts.format)).Int("chunkSize", opts.chunkSize).Msg("⏳ Importando relaciones...")
+		report := newReport()
+		chunk := make([]store.ImportRow, 0, opts.chunkSize)
+		for {
+			row, err := reader.next()
+			if err == io.EOF {
+				break
+			}
+			report.Rows++
+			if err != nil {
+				report.fail(row.Line, fmt.Errorf("the rest of the body couldn't be read: %w", err))
+				break
+			}
+			if row.Err != nil {
+				report.fail(row.Line, row.Err)
+				continue
+			}
+
+			from, hasFrom := row.Values[FromColumn]
+			to, hasTo := row.Values[ToColumn]
+			if !hasFrom || !hasTo {
+				report.fail(row.Line, fmt.Errorf("`%s` and `%s` are required", FromColumn, ToColumn))
+				continue
+			}
+			delete(row.Values, FromColumn)
+			delete(row.Values, ToColumn)
+
+			origin, destination := []string{spec.Origin.Label}, []string{spec.Destination.Label}
+			if err := registry.CheckRelation(spec.Type, origin, destination, row.Values); err != nil {
+				report.fail(row.Line, err)
+				continue
+			}
+
+			chunk = append(chunk, store.ImportRow{Line: row.Line, Properties: row.Values, From: from, To: to})
+			if len(chunk) == opts.chunkSize {
+				importRelations(ctx, db, report, spec, chunk)
+				chunk = chunk[:0]
+			}
+		}
+		importRelations(ctx, db, report, spec, chunk)
+
+		log.Info().Int("imported", report.Imported).Int("failed", report.Failed).Msg("✅ Importación de relaciones terminada")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report.result())
+	}
+}
+
+// readRelationImport reads the URL queries describing the relationships. If
+// they're invalid an error response is sent and ok is false.
+func readRelationImport(w http.ResponseWriter, r *http.Request) (spec store.RelationImport, ok bool) {
+	url_queries := r.URL.Query()
+	fields := []struct {
+		name  string
+		kind  identifier.Kind
+		value *string
+	}{
+		{"RelationType", identifier.KindRelationType, &spec.Type},
+		{"OriginType", identifier.KindLabel, &spec.Origin.Label},
+		{"OriginKey", identifier.KindPropertyKey, &spec.Origin.Key},
+		{"DestinationType", identifier.KindLabel, &spec.Destination.Label},
+		{"DestinationKey", identifier.KindPropertyKey, &spec.Destination.Key},
+	}
+
+	var missing []string
+	for _, field := range fields {
+		*field.value = url_queries.Get(field.name)
+		if *field.value == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		apierror.MissingFields(w, r, missing...)
+		return spec, false
+	}
+
+	for _, field := range fields {
+		if err := identifier.Validate(field.kind, *field.value); err != nil {
+			apierror.InvalidField(w, r, field.name, err)
+			return spec, false
+		}
+	}
+	return spec, true
+}
+
+func importRelations(ctx context.Context, db store.GraphStore, report *report, spec store.RelationImport, chunk []store.ImportRow) {
+	if len(chunk) == 0 {
+		return
+	}
+
+	lines, err := db.ImportRelations(ctx, spec, chunk)
+	if err != nil {
+		log.Error().Err(err).Int("rows", len(chunk)).Msg("❌ Error importando relaciones")
+		for _, row := range chunk {
+			report.fail(row.Line, fmt.Errorf("the chunk couldn't be written: %w", err))
+		}
+		return
+	}
+
+	imported := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		imported[line] = true
+	}
+	for _, row := range chunk {
+		if imported[row.Line] {
+			report.Imported++
+			continue
+		}
+		report.fail(row.Line, fmt.Errorf("no %s has %s `%v` or no %s has %s `%v`",
+			spec.Origin.Label, spec.Origin.Key, row.From, spec.Destination.Label, spec.Destination.Key, row.To))
+	}
+}
@@ -0,0 +1,49 @@
+// Package introspection implements `GET /schema`, which describes the labels
+// and relationship types found in the graph.
+package introspection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultSampleSize is how many elements of each label and type are read
+	// to infer property types when the `sampleSize` URL query isn't sent.
+	DefaultSampleSize = 100
+	MaxSampleSize     = 10000
+)
+
+// NewSchemaHandler handles `GET /schema`. Labels and relationship types are
+// counted exactly, their property keys and types come from a sample.
+func NewSchemaHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sampleSize := DefaultSampleSize
+		if raw := r.URL.Query().Get("sampleSize"); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil || value <= 0 || value > MaxSampleSize {
+				apierror.InvalidField(w, r, "sampleSize", fmt.Errorf("it must be an integer between 1 and %d", MaxSampleSize))
+				return
+			}
+			sampleSize = value
+		}
+
+		log.Info().Int("sampleSize", sampleSize).Msg("⏳ Leyendo el esquema del grafo...")
+		schema, err := db.Introspect(r.Context(), sampleSize)
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error leyendo el esquema")
+			apierror.StoreError(w, r, err, "")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.GraphSchema(schema, sampleSize))
+	}
+}
@@ -1,91 +1,108 @@
 package node
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
-	"strings"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
-
 type NodeRequest struct {
 	NodeType   string         `json:"NodeType"`
 	Properties map[string]any `json:"Properties"`
+	// Keys turns the request into an upsert, the node with the same label and
+	// values for these properties is updated instead of creating a duplicate.
+	Keys []string `json:"Keys,omitempty"`
 }
 
-func NewCreateNodeHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+// NewCreateNodeHandler handles `POST /node`. With `Keys` it upserts the node
+// and answers whether it was created or matched. Since an upsert may create the
+// node it must satisfy the schema as a whole in both cases.
+func NewCreateNodeHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		var req NodeRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid JSON: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
 		if req.NodeType == "" || len(req.Properties) == 0 {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("400 Bad Request - `NodeType` y `Properties` son requeridos"))
+			apierror.MissingFields(w, r, "NodeType", "Properties")
 			return
 		}
 
-		var queryBuilder strings.Builder
-		queryBuilder.WriteString("CREATE (n:")
-		queryBuilder.WriteString(req.NodeType)
-		queryBuilder.WriteString(" {")
-
-		params := make(map[string]any)
-		i := 0
-		for key, value := range req.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $")
-			queryBuilder.WriteString(key)
-			params[key] = value
-			i++
+		if err := identifier.Validate(identifier.KindLabel, req.NodeType); err != nil {
+			apierror.InvalidField(w, r, "NodeType", err)
+			return
 		}
-		queryBuilder.WriteString("}) RETURN n")
 
-		query := queryBuilder.String()
+		if err := identifier.PropertyKeys(req.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
+		}
 
-		log.Info().Str("query", query).Msg("⏳ Creando nodo en Neo4j...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		if err := registry.CheckNode([]string{req.NodeType}, req.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
+		}
 
-		if err != nil {
-			log.Error().Err(err).Msg("❌ Error al crear el nodo en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("500 Internal Server Error - Neo4j Create Error: %s", err.Error())))
+		if len(req.Keys) > 0 {
+			upsertNode(w, r, db, req)
 			return
 		}
 
-		if len(result.Records) == 0 {
-			log.Warn().Msg("⚠ No se pudo crear el nodo")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - Node was not created"))
+		log.Info().Str("nodeType", req.NodeType).Msg("⏳ Creando nodo...")
+		createdNode, err := db.CreateNode(ctx, req.NodeType, req.Properties)
+
+		if err != nil {
+			log.Error().Err(err).Msg("❌ Error al crear el nodo")
+			apierror.DBError(w, r, err)
 			return
 		}
-		
-		record := result.Records[0]
-		createdNode, found := record.Get("n")
-		
-		if !found {
-			log.Warn().Msg("⚠ No se pudo recuperar el nodo creado")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - Created node not found"))
+
+		if createdNode.ElementId == "" {
+			log.Warn().Msg("⚠ No se pudo crear el nodo")
+			apierror.Internal(w, r, "The node was not created")
 			return
 		}
-		
+
 		// ✅ Enviar respuesta con el nodo creado
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(createdNode)
+		json.NewEncoder(w).Encode(dto.Node(createdNode))
+
+	}
+}
 
+func upsertNode(w http.ResponseWriter, r *http.Request, db store.GraphStore, req NodeRequest) {
+	keys := make(map[string]any, len(req.Keys))
+	properties := maps.Clone(req.Properties)
+	for _, key := range req.Keys {
+		value, found := req.Properties[key]
+		if !found || value == nil {
+			apierror.InvalidField(w, r, "Keys", fmt.Errorf("`%s` must have a value in `Properties`", key))
+			return
+		}
+		keys[key] = value
+		delete(properties, key)
 	}
-		
+
+	log.Info().Str("nodeType", req.NodeType).Strs("keys", req.Keys).Msg("⏳ Haciendo upsert del nodo...")
+	merge, err := db.MergeNode(r.Context(), req.NodeType, keys, properties)
+	if err != nil {
+		log.Error().Err(err).Msg("❌ Error en el upsert del nodo")
+		apierror.DBError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.NodeMerge(merge))
 }
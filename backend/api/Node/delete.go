@@ -2,13 +2,13 @@ package node
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,77 +17,53 @@ type reqBody struct {
 	Properties map[string]any
 }
 
-func NewDeleteNodeHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewDeleteNodeHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		decoder := json.NewDecoder(r.Body)
 		var body reqBody
 
 		err := decoder.Decode(&body)
 		if err != nil {
-			msg := fmt.Sprintf("BAD REQUEST - INVALID BODY! `%s`", err.Error())
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(msg))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
-		// MATCH (n:$NodeType {$nodeName: $nodeValue})
-		// DETACH DELETE n
-		// RETURN n
-		// LIMIT 1
-		b := strings.Builder{}
-		b.WriteString("MATCH (n:")
-		b.WriteString(body.NodeType)
-		b.WriteString(" {")
-
-		i := 1
-		max_i := len(body.Properties)
-		for property := range body.Properties {
-			b.WriteString(property)
-			b.WriteString(": ")
-			b.WriteRune('$')
-			b.WriteString(property)
+		if err := identifier.Validate(identifier.KindLabel, body.NodeType); err != nil {
+			apierror.InvalidField(w, r, "NodeType", err)
+			return
+		}
 
-			if i != max_i {
-				b.WriteRune(',')
-			}
+		if err := identifier.PropertyKeys(body.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
 		}
-		b.WriteString("}) DETACH DELETE n LIMIT 1 RETURN n")
 
-		query := b.String()
-		log.Info().Str("query", query).Msg("Querying DB...")
-		result, err := neo4j.ExecuteQuery(
-			ctx, *client, query,
-			body.Properties,
-			neo4j.EagerResultTransformer,
-			neo4j.ExecuteQueryWithDatabase("neo4j"),
-		)
+		nodes, err := db.DeleteNodes(ctx, store.Object{Category: body.NodeType, Properties: body.Properties}, 1)
 
 		if err != nil {
 			log.Error().Err(err).Msg("Error querying DB!")
-			w.WriteHeader(http.StatusInternalServerError)
-			msg := fmt.Sprintf("INTERNAL SERVER ERROR - QUERY ERROR `%s`", err.Error())
-			w.Write([]byte(msg))
+			apierror.DBError(w, r, err)
+			return
 		}
-		nodeCount := len(result.Records)
+		nodeCount := len(nodes)
 		log.Info().Int("recordCount", nodeCount).Msg("Done!")
 
 		if nodeCount == 0 {
 			log.Error().Err(err).Msg("No node found!")
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 ERROR - NOT FOUND"))
+			apierror.NotFound(w, r, "No node matched the request")
 			return
 		}
 
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records[0])
+		node := dto.Node(nodes[0])
+		err = enc.Encode(node)
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("row", node).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
@@ -2,13 +2,15 @@ package node
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,85 +20,81 @@ type deleteManyRequest struct {
 	Limit      int `json:"Limit,omitempty"`
 }
 
-func NewDeleteManyNodesHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewDeleteManyNodesHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
+
+		dryRun, err := utils.DryRun(r)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
 
 		decoder := json.NewDecoder(r.Body)
 		var body deleteManyRequest
 
-		err := decoder.Decode(&body)
+		err = decoder.Decode(&body)
 		if err != nil {
-			msg := fmt.Sprintf("BAD REQUEST - INVALID BODY! `%s`", err.Error())
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(msg))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
-		// MATCH (n:$NodeType {$nodeName: $nodeValue})
-		// WITH n
-		// LIMIT 1
-		// DETACH DELETE n
-		// RETURN n
-		b := strings.Builder{}
-		b.WriteString("MATCH (n:")
-		b.WriteString(body.NodeType)
-		b.WriteString(" {")
-
-		i := 1
-		log.Debug().Msg(fmt.Sprintf("%v", body.Properties))
-		max_i := len(body.Properties)
-		for property, value := range body.Properties {
-			b.WriteString(property)
-			b.WriteString(": ")
-			b.WriteString(fmt.Sprintf("%v", value))
-
-			if i != max_i {
-				b.WriteRune(',')
-			}
+		if err := identifier.Validate(identifier.KindLabel, body.NodeType); err != nil {
+			apierror.InvalidField(w, r, "NodeType", err)
+			return
 		}
 
-		b.WriteString("}) WITH n ")
-		if body.Limit > 0 {
-			b.WriteString(fmt.Sprintf("LIMIT %d ", body.Limit))
+		equal, where, err := filter.Parse(body.Properties)
+		if err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
 		}
 
-		// Detaching, deleting and setting limit
-		b.WriteString("DETACH DELETE n RETURN n")
+		match := store.Object{Category: body.NodeType, Properties: equal, Where: where}
 
-		query := b.String()
-		log.Info().Str("query", query).Msg("Querying DB...")
-		result, err := neo4j.ExecuteQuery(
-			ctx, *client, query,
-			body.Properties,
-			neo4j.EagerResultTransformer,
-			neo4j.ExecuteQueryWithDatabase("neo4j"),
-		)
+		if dryRun {
+			preview, err := db.PreviewNodes(ctx, match, body.Limit, utils.DryRunSampleSize)
+			if err != nil {
+				log.Error().Err(err).Msg("Error querying DB!")
+				apierror.DBError(w, r, err)
+				return
+			}
+			log.Info().Int64("affected", preview.Affected).Msg("Dry run, no node deleted")
+
+			var buff bytes.Buffer
+			err = json.NewEncoder(&buff).Encode(dto.NodePreview(preview))
+			if err != nil {
+				log.Error().Err(err).Interface("preview", preview).Msg("Error encoding preview!")
+				apierror.Internal(w, r, "The response couldn't be encoded")
+				return
+			}
+			w.Write(buff.Bytes())
+			return
+		}
+
+		nodes, err := db.DeleteNodes(ctx, match, body.Limit)
 
 		if err != nil {
 			log.Error().Err(err).Msg("Error querying DB!")
-			w.WriteHeader(http.StatusInternalServerError)
-			msg := fmt.Sprintf("INTERNAL SERVER ERROR - QUERY ERROR `%s`", err.Error())
-			w.Write([]byte(msg))
+			apierror.DBError(w, r, err)
+			return
 		}
-		nodeCount := len(result.Records)
+		nodeCount := len(nodes)
 		log.Info().Int("recordCount", nodeCount).Msg("Done!")
 
 		if nodeCount == 0 {
 			log.Error().Err(err).Msg("No node found!")
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 ERROR - NOT FOUND"))
+			apierror.NotFound(w, r, "No node matched the request")
 			return
 		}
 
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records)
+		err = enc.Encode(dto.Nodes(nodes))
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("rows", nodes).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
@@ -0,0 +1,108 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type UpdateNodeByIdRequest struct {
+	Properties map[string]any `json:"Properties"`
+}
+
+// NewReadNodeByIdHandler handles `GET /node/{elementId}`.
+func NewReadNodeByIdHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		node, err := db.GetNode(ctx, elementId)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error reading node!")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.Node(node))
+	}
+}
+
+// NewUpdateNodeByIdHandler handles `PUT /node/{elementId}`. The node is read
+// first to validate the properties against the schema of its labels.
+func NewUpdateNodeByIdHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		var req UpdateNodeByIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
+		}
+
+		if len(req.Properties) == 0 {
+			apierror.MissingFields(w, r, "Properties")
+			return
+		}
+
+		if err := identifier.PropertyKeys(req.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
+		}
+
+		if registry != nil {
+			node, err := db.GetNode(ctx, elementId)
+			if err != nil {
+				log.Error().Err(err).Str("elementId", elementId).Msg("Error reading node!")
+				apierror.StoreError(w, r, err, notFoundMessage(elementId))
+				return
+			}
+			if err := registry.CheckNodeUpdate(node.Labels, req.Properties); err != nil {
+				apierror.InvalidField(w, r, "Properties", err)
+				return
+			}
+		}
+
+		log.Info().Str("elementId", elementId).Msg("Ejecutando actualización...")
+		update, err := db.UpdateNode(ctx, elementId, req.Properties)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error actualizando el nodo")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.NodeUpdate(update))
+	}
+}
+
+// NewDeleteNodeByIdHandler handles `DELETE /node/{elementId}`.
+func NewDeleteNodeByIdHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		node, err := db.DeleteNode(ctx, elementId)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error deleting node!")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.Node(node))
+	}
+}
+
+func notFoundMessage(elementId string) string {
+	return fmt.Sprintf("No node with element ID `%s` exists", elementId)
+}
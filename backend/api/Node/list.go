@@ -0,0 +1,102 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// NewListNodesHandler lists the nodes matching the `NodeType` and optional
+// `Properties` queries. The page is selected with `Skip` and `Limit` and
+// sorted with `SortBy` (a property key) and `Order` (`asc` or `desc`).
+func NewListNodesHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		match, ok := readNodeQuery(w, r, false)
+		if !ok {
+			return
+		}
+
+		url_queries := r.URL.Query()
+		listing := store.NodeListing{Match: match, Limit: defaultPageSize}
+
+		if skip := url_queries.Get("Skip"); skip != "" {
+			value, err := strconv.Atoi(skip)
+			if err != nil || value < 0 {
+				apierror.InvalidField(w, r, "Skip", fmt.Errorf("`%s` is not a positive integer", skip))
+				return
+			}
+			listing.Skip = value
+		}
+
+		if limit := url_queries.Get("Limit"); limit != "" {
+			value, err := strconv.Atoi(limit)
+			if err != nil || value <= 0 || value > maxPageSize {
+				apierror.InvalidField(w, r, "Limit", fmt.Errorf("`%s` is not an integer between 1 and %d", limit, maxPageSize))
+				return
+			}
+			listing.Limit = value
+		}
+
+		if sortBy := url_queries.Get("SortBy"); sortBy != "" {
+			if err := identifier.Validate(identifier.KindPropertyKey, sortBy); err != nil {
+				apierror.InvalidField(w, r, "SortBy", err)
+				return
+			}
+			listing.SortBy = sortBy
+		}
+
+		switch order := strings.ToLower(url_queries.Get("Order")); order {
+		case "", "asc":
+		case "desc":
+			listing.Descending = true
+		default:
+			apierror.InvalidField(w, r, "Order", fmt.Errorf("`%s` must be `asc` or `desc`", order))
+			return
+		}
+
+		page, err := db.ListNodes(ctx, listing)
+
+		if err != nil {
+			log.Error().Err(err).Msg("Error querying DB!")
+			apierror.DBError(w, r, err)
+			return
+		}
+		log.Info().Int("recordCount", len(page.Nodes)).Int64("total", page.Total).Msg("Done!")
+
+		response := dto.NodePageDTO{
+			Nodes: dto.Nodes(page.Nodes),
+			Total: page.Total,
+			Skip:  listing.Skip,
+			Limit: listing.Limit,
+		}
+
+		var buff bytes.Buffer
+		enc := json.NewEncoder(&buff)
+
+		err = enc.Encode(response)
+		if err != nil {
+			log.Error().Err(err).Interface("page", response).Msg("Error encoding page!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buff.Bytes())
+	}
+}
@@ -0,0 +1,127 @@
+package node_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+func newRouter() http.Handler {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	app := api.NewApi(memstore.New(), nil)
+
+	r := chi.NewRouter()
+	r.Post("/node", app.CreateNodeHandler)
+	r.Get("/node", app.ReadNodeHandler)
+	r.Put("/node", app.UpdateNodeHandler)
+	r.Delete("/node", app.DeleteNodeHandler)
+	r.Get("/node/{elementId}", app.ReadNodeByIdHandler)
+	r.Put("/node/{elementId}", app.UpdateNodeByIdHandler)
+	r.Delete("/node/{elementId}", app.DeleteNodeByIdHandler)
+	return r
+}
+
+// send makes a request and decodes the JSON response into out, when it isn't nil.
+func send(t *testing.T, router http.Handler, method string, target string, body string, wantStatus int, out any) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if w.Code != wantStatus {
+		t.Fatalf("%s %s answered %d, want %d: %s", method, target, w.Code, wantStatus, w.Body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s answered invalid JSON: %v", method, target, err)
+		}
+	}
+}
+
+func readQuery(properties string) string {
+	return "/node?" + url.Values{"NodeType": {"Product"}, "Properties": {properties}}.Encode()
+}
+
+func TestNodeCRUD(t *testing.T) {
+	router := newRouter()
+
+	var created dto.NodeDTO
+	send(t, router, http.MethodPost, "/node", `{"NodeType": "Product", "Properties": {"id": "P1", "name": "Silla"}}`, http.StatusOK, &created)
+	if created.ElementId == "" || created.Labels[0] != "Product" || created.Properties["name"] != "Silla" {
+		t.Fatalf("unexpected created node %+v", created)
+	}
+
+	var read dto.NodeDTO
+	send(t, router, http.MethodGet, readQuery(`{"id": "P1"}`), "", http.StatusOK, &read)
+	if read.ElementId != created.ElementId {
+		t.Errorf("GET /node returned %s, want %s", read.ElementId, created.ElementId)
+	}
+
+	var update dto.NodeUpdateDTO
+	send(t, router, http.MethodPut, "/node", `{"NodeType": "Product", "Identifier": {"id": "P1"}, "Properties": {"price": 250}}`, http.StatusOK, &update)
+	if _, found := update.Before.Properties["price"]; found || update.After.Properties["price"] != 250.0 {
+		t.Errorf("unexpected update %+v", update)
+	}
+
+	var readById dto.NodeDTO
+	send(t, router, http.MethodGet, "/node/"+created.ElementId, "", http.StatusOK, &readById)
+	if readById.Properties["price"] != 250.0 {
+		t.Errorf("GET /node/{elementId} didn't return the update: %+v", readById)
+	}
+
+	var updateById dto.NodeUpdateDTO
+	send(t, router, http.MethodPut, "/node/"+created.ElementId, `{"Properties": {"name": "Silla alta", "price": null}}`, http.StatusOK, &updateById)
+	if _, hasPrice := updateById.After.Properties["price"]; hasPrice || updateById.After.Properties["name"] != "Silla alta" {
+		t.Errorf("PUT /node/{elementId} didn't update the node: %+v", updateById)
+	}
+
+	var deleted dto.NodeDTO
+	send(t, router, http.MethodDelete, "/node", `{"NodeType": "Product", "Properties": {"id": "P1"}}`, http.StatusOK, &deleted)
+	if deleted.ElementId != created.ElementId {
+		t.Errorf("DELETE /node deleted %s, want %s", deleted.ElementId, created.ElementId)
+	}
+	send(t, router, http.MethodGet, readQuery(`{"id": "P1"}`), "", http.StatusNotFound, nil)
+	send(t, router, http.MethodGet, "/node/"+created.ElementId, "", http.StatusNotFound, nil)
+}
+
+func TestDeleteNodeById(t *testing.T) {
+	router := newRouter()
+
+	var created dto.NodeDTO
+	send(t, router, http.MethodPost, "/node", `{"NodeType": "Provider", "Properties": {"id": "PR1"}}`, http.StatusOK, &created)
+	send(t, router, http.MethodDelete, "/node/"+created.ElementId, "", http.StatusOK, nil)
+	send(t, router, http.MethodDelete, "/node/"+created.ElementId, "", http.StatusNotFound, nil)
+}
+
+func TestNodeValidation(t *testing.T) {
+	router := newRouter()
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"create without properties", http.MethodPost, "/node", `{"NodeType": "Product"}`, http.StatusBadRequest},
+		{"create with invalid label", http.MethodPost, "/node", `{"NodeType": "Bad Label", "Properties": {"id": 1}}`, http.StatusBadRequest},
+		{"create with invalid JSON", http.MethodPost, "/node", `{`, http.StatusBadRequest},
+		{"read without label", http.MethodGet, "/node?Properties=%7B%7D", "", http.StatusBadRequest},
+		{"read with invalid filter", http.MethodGet, readQuery(`{"id": {"$nope": 1}}`), "", http.StatusBadRequest},
+		{"update without identifier", http.MethodPut, "/node", `{"NodeType": "Product", "Properties": {"id": 1}}`, http.StatusBadRequest},
+		{"update unknown node", http.MethodPut, "/node", `{"NodeType": "Product", "Identifier": {"id": "X"}, "Properties": {"id": 1}}`, http.StatusNotFound},
+		{"delete unknown node", http.MethodDelete, "/node", `{"NodeType": "Product", "Properties": {"id": "X"}}`, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			send(t, router, test.method, test.target, test.body, test.wantStatus, nil)
+		})
+	}
+}
@@ -2,100 +2,97 @@ package node
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
-func NewReadNodeHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewReadNodeHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
-		url_queries := r.URL.Query()
-		nodeType := url_queries.Get("NodeType")
+		ctx := r.Context()
 
-		if nodeType == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("BAD REQUEST - NO `NodeType` query!"))
+		match, ok := readNodeQuery(w, r, true)
+		if !ok {
 			return
 		}
 
-		properties := url_queries.Get("Properties")
-		if properties == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("BAD REQUEST - NO `Properties` query!"))
-			return
-		}
-
-		decoder := json.NewDecoder(strings.NewReader(properties))
-		var nodeProperties map[string]any
-
-		err := decoder.Decode(&nodeProperties)
-		if err != nil {
-			msg := fmt.Sprintf("BAD REQUEST - INVALID `Properties` array! `%s`", err.Error())
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(msg))
-			return
-		}
-
-		// MATCH (n:$NodeType {$nodeName: $nodeValue})
-		// RETURN n
-		// LIMIT 1
-		b := strings.Builder{}
-		b.WriteString("MATCH (n:")
-		b.WriteString(nodeType)
-		b.WriteString(" {")
-
-		i := 1
-		max_i := len(nodeProperties)
-		for property := range nodeProperties {
-			b.WriteString(property)
-			b.WriteString(": ")
-			b.WriteRune('$')
-			b.WriteString(property)
-
-			if i != max_i {
-				b.WriteRune(',')
-			}
-		}
-		b.WriteString("}) RETURN n LIMIT 1")
-
-		query := b.String()
-		log.Info().Str("query", query).Msg("Querying DB...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, nodeProperties, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		nodes, err := db.FindNodes(ctx, match, 1)
 
 		if err != nil {
 			log.Error().Err(err).Msg("Error querying DB!")
-			w.WriteHeader(http.StatusInternalServerError)
-			msg := fmt.Sprintf("INTERNAL SERVER ERROR - QUERY ERROR `%s`", err.Error())
-			w.Write([]byte(msg))
+			apierror.DBError(w, r, err)
 			return
 		}
-		nodeCount := len(result.Records)
+		nodeCount := len(nodes)
 		log.Info().Int("recordCount", nodeCount).Msg("Done!")
 
 		if nodeCount == 0 {
 			log.Error().Err(err).Msg("No node found!")
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 ERROR - NOT FOUND"))
+			apierror.NotFound(w, r, "No node matched the request")
 			return
 		}
 
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records[0])
+		node := dto.Node(nodes[0])
+		err = enc.Encode(node)
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("row", node).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
 		w.Write(buff.Bytes())
 	}
 }
+
+// readNodeQuery reads the `NodeType` and `Properties` URL queries, `Properties`
+// is a JSON filter (see the filter package). If they're invalid an error
+// response is sent and ok is false.
+func readNodeQuery(w http.ResponseWriter, r *http.Request, requireProperties bool) (match store.Object, ok bool) {
+	url_queries := r.URL.Query()
+	nodeType := url_queries.Get("NodeType")
+
+	if nodeType == "" {
+		apierror.MissingFields(w, r, "NodeType")
+		return match, false
+	}
+
+	properties := url_queries.Get("Properties")
+	if properties == "" && requireProperties {
+		apierror.MissingFields(w, r, "Properties")
+		return match, false
+	}
+
+	var nodeProperties map[string]any
+	if properties != "" {
+		decoder := json.NewDecoder(strings.NewReader(properties))
+
+		err := decoder.Decode(&nodeProperties)
+		if err != nil {
+			apierror.InvalidJSON(w, r, "Properties", err)
+			return match, false
+		}
+	}
+
+	if err := identifier.Validate(identifier.KindLabel, nodeType); err != nil {
+		apierror.InvalidField(w, r, "NodeType", err)
+		return match, false
+	}
+
+	equal, where, err := filter.Parse(nodeProperties)
+	if err != nil {
+		apierror.InvalidField(w, r, "Properties", err)
+		return match, false
+	}
+
+	return store.Object{Category: nodeType, Properties: equal, Where: where}, true
+}
@@ -2,14 +2,15 @@ package properties
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -19,71 +20,90 @@ type UpdateNodeRequest struct {
 	Limit            *int
 }
 
-func NewUpdatePropertiesHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewUpdatePropertiesHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		if r.Method != http.MethodPut {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte("405 Method Not Allowed - Use PUT"))
+			apierror.MethodNotAllowed(w, r, http.MethodPut)
 			return
 		}
 
-		var req UpdateNodeRequest
-		err := json.NewDecoder(r.Body).Decode(&req)
+		dryRun, err := utils.DryRun(r)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write(fmt.Appendf(nil, "400 Bad Request - Invalid JSON: %s", err.Error()))
+			apierror.InvalidField(w, r, "", err)
 			return
 		}
 
-		var b strings.Builder
-		b.WriteString("MATCH ")
-		req.Target.AppendAsNeo4JMatch(&b, []string{"(", ")"}, "n1")
-
-		for prop := range req.UpdateProperties {
-			b.WriteString(" SET ")
-			b.WriteString("n1.")
-			b.WriteString(prop)
-			b.WriteString(" = ")
-			b.WriteString("$new_")
-			b.WriteString(prop)
+		var req UpdateNodeRequest
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
 		}
 
-		params := make(map[string]any)
-		for prop, val := range req.UpdateProperties {
-			params["new_"+prop] = val
+		target, err := req.Target.Filter("Target", identifier.KindLabel)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
 		}
-		for prop, val := range req.Target.Properties {
-			params["n1_"+prop] = val
+
+		if err := identifier.PropertyKeys(req.UpdateProperties); err != nil {
+			apierror.InvalidField(w, r, "UpdateProperties", err)
+			return
 		}
 
-		b.WriteString(" RETURN n1 ")
+		if err := registry.CheckNodeUpdate([]string{target.Category}, req.UpdateProperties); err != nil {
+			apierror.InvalidField(w, r, "UpdateProperties", err)
+			return
+		}
 
+		limit := 0
 		if req.Limit != nil {
-			b.WriteString("LIMIT ")
-			b.WriteString(fmt.Sprint(*req.Limit))
+			limit = *req.Limit
 		}
 
-		query := b.String()
-		log.Info().Str("query", query).Msg("⏳ Ejecutando consulta de actualización en Neo4j...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		if dryRun {
+			preview, err := db.PreviewNodes(ctx, target, limit, utils.DryRunSampleSize)
+			if err != nil {
+				log.Error().Err(err).Msg("Error querying DB!")
+				apierror.DBError(w, r, err)
+				return
+			}
+			log.Info().Int64("affected", preview.Affected).Msg("Dry run, no property updated")
+
+			var buff bytes.Buffer
+			err = json.NewEncoder(&buff).Encode(dto.NodePreview(preview))
+			if err != nil {
+				log.Error().Err(err).Interface("preview", preview).Msg("Error encoding preview!")
+				apierror.Internal(w, r, "The response couldn't be encoded")
+				return
+			}
+			w.Write(buff.Bytes())
+			return
+		}
+
+		log.Info().Msg("⏳ Ejecutando actualización de propiedades...")
+		updates, err := db.UpdateNodes(ctx, target, req.UpdateProperties, limit)
 
 		if err != nil {
-			log.Error().Err(err).Msg("❌ Error actualizando en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write(fmt.Appendf(nil, "500 Internal Server Error - Neo4j Update Error: %s", err.Error()))
+			log.Error().Err(err).Msg("❌ Error actualizando propiedades")
+			apierror.DBError(w, r, err)
 			return
 		}
 
+		nodes := make([]store.Node, 0, len(updates))
+		for _, update := range updates {
+			nodes = append(nodes, update.Node)
+		}
+
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records)
+		err = enc.Encode(dto.Nodes(nodes))
 		if err != nil {
-			log.Error().Err(err).Interface("array", result.Records[0]).Msg("Error encoding array!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("array", nodes).Msg("Error encoding array!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
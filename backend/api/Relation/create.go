@@ -1,14 +1,14 @@
 package relation
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,125 +18,55 @@ type CreateRelationRequest struct {
 	Relation        utils.Neo4JObject `json:"Relation"`
 }
 
-type CreateResponse struct {
-	CreatedRelation map[string]any `json:"CreatedRelation"`
-}
-
-func NewCreateRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewCreateRelationHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		if r.Method != http.MethodPost {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte("405 Method Not Allowed - Use POST"))
+			apierror.MethodNotAllowed(w, r, http.MethodPost)
 			return
 		}
 
 		var req CreateRelationRequest
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid JSON: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
 		if req.OriginNode.Category == "" || req.DestinationNode.Category == "" || req.Relation.Category == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("400 Bad Request - `OriginNode`, `DestinationNode`, y `Relation` son requeridos"))
+			apierror.MissingFields(w, r, "OriginNode", "DestinationNode", "Relation")
 			return
 		}
 
-		var queryBuilder strings.Builder
-		queryBuilder.WriteString("MATCH (n1:")
-		queryBuilder.WriteString(req.OriginNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i := 0
-		for key := range req.OriginNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n1_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("}), (n2:")
-		queryBuilder.WriteString(req.DestinationNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i = 0
-		for key := range req.DestinationNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n2_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("}) MERGE (n1)-[r:")
-		queryBuilder.WriteString(req.Relation.Category)
-		queryBuilder.WriteString(" {")
-
-		i = 0
-		for key := range req.Relation.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $r_")
-			queryBuilder.WriteString(key)
-			i++
+		if err := utils.ValidateRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
 		}
-		queryBuilder.WriteString("}]->(n2) RETURN properties(r) AS createdRelation, type(r) AS relationType, startNode(r) AS startNode, endNode(r) AS endNode")
 
-		query := queryBuilder.String()
-
-		params := make(map[string]any)
-
-		for property, val := range req.OriginNode.Properties {
-			params["n1_"+property] = val
-		}
-		for property, val := range req.Relation.Properties {
-			params["r_"+property] = val
-		}
-		for property, val := range req.DestinationNode.Properties {
-			params["n2_"+property] = val
+		origin, destination := []string{req.OriginNode.Category}, []string{req.DestinationNode.Category}
+		if err := registry.CheckRelation(req.Relation.Category, origin, destination, req.Relation.Properties); err != nil {
+			apierror.InvalidField(w, r, "Relation", err)
+			return
 		}
 
-		log.Info().Str("query", query).Msg("Creando relación en Neo4j...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		log.Info().Msg("Creando relación...")
+		matches, err := db.CreateRelation(ctx, utils.ToRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation))
 
 		if err != nil {
-			log.Error().Err(err).Msg("Error al crear la relación en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("500 Internal Server Error - Neo4j Create Error: %s", err.Error())))
+			log.Error().Err(err).Msg("Error al crear la relación")
+			apierror.DBError(w, r, err)
 			return
 		}
 
-		if len(result.Records) == 0 {
+		if len(matches) == 0 {
 			log.Warn().Msg("No se encontró la relación creada")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - Relationship was not created"))
+			apierror.NotFound(w, r, "No nodes matched `OriginNode` and `DestinationNode`")
 			return
 		}
 
-		// Return de datos para la relacion creada 
-		record := result.Records[0]
-		createdRelation, _ := record.Get("createdRelation")
-		relationType, _ := record.Get("relationType")
-		startNode, _ := record.Get("startNode")
-		endNode, _ := record.Get("endNode")
-
-		response := CreateResponse{
-			CreatedRelation: map[string]any{
-				"type":       relationType,
-				"properties": createdRelation,
-				"startNode":  startNode,
-				"endNode":    endNode,
-			},
-		}
+		// Return de datos para la relacion creada
+		response := dto.RelationMatch(matches[0])
 
 		log.Info().Interface("Created Relation", response).Msg("Relación creada correctamente")
 
@@ -1,14 +1,13 @@
 package relation
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -18,118 +17,48 @@ type DeleteRelationRequest struct {
 	Relation        utils.Neo4JObject `json:"Relation"`
 }
 
-func NewDeleteRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewDeleteRelationHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		if r.Method != http.MethodDelete {
-			http.Error(w, "405 Method Not Allowed - Use DELETE", http.StatusMethodNotAllowed)
+			apierror.MethodNotAllowed(w, r, http.MethodDelete)
 			return
 		}
 
 		var req DeleteRelationRequest
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("400 Bad Request - Invalid JSON: %s", err.Error()), http.StatusBadRequest)
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
 		if req.OriginNode.Category == "" || req.DestinationNode.Category == "" || req.Relation.Category == "" {
-			http.Error(w, "400 Bad Request - OriginNode, DestinationNode, y Relation son requeridos", http.StatusBadRequest)
+			apierror.MissingFields(w, r, "OriginNode", "DestinationNode", "Relation")
 			return
 		}
 
-		var queryBuilder strings.Builder
-		queryBuilder.WriteString("MATCH (n1:")
-		queryBuilder.WriteString(req.OriginNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i := 0
-		for key := range req.OriginNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n1_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("})-[r:")
-		queryBuilder.WriteString(req.Relation.Category)
-		queryBuilder.WriteString("]->(n2:")
-		queryBuilder.WriteString(req.DestinationNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i = 0
-		for key := range req.DestinationNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n2_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("}) DELETE r RETURN COUNT(r) AS deletedCount")
-
-		query := queryBuilder.String()
-
-		params := make(map[string]any)
-		for property, val := range req.OriginNode.Properties {
-			params["n1_"+property] = val
-		}
-		for property, val := range req.Relation.Properties {
-			params["r_"+property] = val
-		}
-		for property, val := range req.DestinationNode.Properties {
-			params["n2_"+property] = val
-		}
-
-		log.Info().Str("query", query).Interface("params", params).Msg("🔍 Ejecutando DELETE en Neo4j...")
-
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
-		if err != nil {
-			log.Error().Err(err).Msg("Error al eliminar la relación en Neo4j")
-			http.Error(w, fmt.Sprintf("500 Internal Server Error - Neo4j Delete Error: %s", err.Error()), http.StatusInternalServerError)
+		if err := utils.ValidateRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation); err != nil {
+			apierror.InvalidField(w, r, "", err)
 			return
 		}
 
-		if len(result.Records) == 0 {
-			log.Warn().Msg("No se encontró la relación para eliminar")
-			http.Error(w, "404 ERROR - No relation found to delete", http.StatusNotFound)
-			return
-		}
-
-		record := result.Records[0]
-
-		deletedCountInterface, found := record.Get("deletedCount")
-		if !found {
-			log.Warn().Msg("No se encontró la relación para eliminar")
-			http.Error(w, "404 ERROR - No relation found to delete", http.StatusNotFound)
-			return
-		}
-
-		deletedCount, ok := deletedCountInterface.(int64)
-		if !ok {
-			log.Error().Msg("Error al convertir deletedCount a int64")
-			http.Error(w, "500 Internal Server Error - Error en la conversión de datos", http.StatusInternalServerError)
-			return
-		}
-
-		if deletedCount == 0 {
-			log.Warn().Msg("No se encontró la relación para eliminar")
-			http.Error(w, "404 ERROR - No relation found to delete", http.StatusNotFound)
+		log.Info().Msg("🔍 Ejecutando DELETE...")
+		deletedCount, err := db.DeleteRelations(ctx, utils.ToRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation), 0)
+		if err != nil {
+			log.Error().Err(err).Msg("Error al eliminar la relación")
+			apierror.DBError(w, r, err)
 			return
 		}
 
 		if deletedCount == 0 {
 			log.Warn().Msg("No se encontró la relación para eliminar")
-			http.Error(w, "404 ERROR - No relation found to delete", http.StatusNotFound)
+			apierror.NotFound(w, r, "No relationship matched the request")
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(req)
+		json.NewEncoder(w).Encode(dto.DeletedDTO{DeletedCount: deletedCount})
 	}
 }
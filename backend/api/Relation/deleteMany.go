@@ -1,14 +1,14 @@
 package relation
 
 import (
-	"context"
+	"bytes"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -19,105 +19,69 @@ type DeleteManyRelationsRequest struct {
 	Limit           int               `json:"Limit,omitempty"`
 }
 
-type DeleteManyResponse struct {
-	DeletedCount int `json:"DeletedCount"`
-}
-
-func NewDeleteManyRelationsHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewDeleteManyRelationsHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		if r.Method != http.MethodDelete {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte("405 Method Not Allowed - Use DELETE"))
+			apierror.MethodNotAllowed(w, r, http.MethodDelete)
+			return
+		}
+
+		dryRun, err := utils.DryRun(r)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
 			return
 		}
 
 		var req DeleteManyRelationsRequest
-		err := json.NewDecoder(r.Body).Decode(&req)
+		err = json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid JSON: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
 		if req.OriginNode.Category == "" || req.DestinationNode.Category == "" || req.Relation.Category == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("400 Bad Request - `OriginNode`, `DestinationNode`, y `Relation` son requeridos"))
+			apierror.MissingFields(w, r, "OriginNode", "DestinationNode", "Relation")
 			return
 		}
 
-		var queryBuilder strings.Builder
-		queryBuilder.WriteString("MATCH (n1:")
-		queryBuilder.WriteString(req.OriginNode.Category)
-		queryBuilder.WriteString(" {")
+		pattern, err := utils.FilterRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
+		}
 
-		i := 0
-		for key := range req.OriginNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
+		if dryRun {
+			preview, err := db.PreviewRelations(ctx, pattern, req.Limit, utils.DryRunSampleSize)
+			if err != nil {
+				log.Error().Err(err).Msg("Error querying DB!")
+				apierror.DBError(w, r, err)
+				return
 			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n1_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("})-[r:")
-		queryBuilder.WriteString(req.Relation.Category)
-		queryBuilder.WriteString("]->(n2:")
-		queryBuilder.WriteString(req.DestinationNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i = 0
-		for key := range req.DestinationNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
+			log.Info().Int64("affected", preview.Affected).Msg("Dry run, no relation deleted")
+
+			var buff bytes.Buffer
+			err = json.NewEncoder(&buff).Encode(dto.RelationPreview(preview))
+			if err != nil {
+				log.Error().Err(err).Interface("preview", preview).Msg("Error encoding preview!")
+				apierror.Internal(w, r, "The response couldn't be encoded")
+				return
 			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n2_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-
-		deleteLimit := ""
-		if req.Limit > 0 {
-			deleteLimit = fmt.Sprintf(" LIMIT %d ", req.Limit)
-		}
-
-		// Aquí cambiamos DELETE r por DELETE r RETURN properties(r) AS deletedRelation
-		queryBuilder.WriteString("}) WITH r ")
-		queryBuilder.WriteString(deleteLimit)
-		queryBuilder.WriteString("DELETE r")
-
-		query := queryBuilder.String()
-
-		params := make(map[string]any)
-
-		for property, val := range req.OriginNode.Properties {
-			params["n1_"+property] = val
-		}
-		for property, val := range req.Relation.Properties {
-			params["r_"+property] = val
-		}
-		for property, val := range req.DestinationNode.Properties {
-			params["n2_"+property] = val
+			w.Write(buff.Bytes())
+			return
 		}
 
-		log.Info().Str("query", query).Msg("Buscando y eliminando relación en Neo4j...")
-		log.Debug().Msg(fmt.Sprintf("%v", params))
-
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		log.Info().Msg("Buscando y eliminando relaciones...")
+		deletedCount, err := db.DeleteRelations(ctx, pattern, req.Limit)
 
 		if err != nil {
-			log.Error().Err(err).Msg("Error al eliminar la relación en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("500 Internal Server Error - Neo4j Delete Error: %s", err.Error())))
+			log.Error().Err(err).Msg("Error al eliminar la relación")
+			apierror.DBError(w, r, err)
 			return
 		}
 
-		response := DeleteManyResponse{
-			DeletedCount: result.Summary.Counters().RelationshipsDeleted(),
-		}
+		response := dto.DeletedDTO{DeletedCount: deletedCount}
 
 		log.Info().Interface("Deleted Relation", response).Msg("Relación eliminada correctamente")
 
@@ -0,0 +1,108 @@
+package relation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+type UpdateRelationByIdRequest struct {
+	NewProperties map[string]any `json:"NewProperties"`
+}
+
+// NewReadRelationByIdHandler handles `GET /relation/{elementId}`.
+func NewReadRelationByIdHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		match, err := db.GetRelation(ctx, elementId)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error consultando la relación")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.RelationMatch(match))
+	}
+}
+
+// NewUpdateRelationByIdHandler handles `PUT /relation/{elementId}`. The
+// relationship is read first to validate the properties against the schema of
+// its type.
+func NewUpdateRelationByIdHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		var req UpdateRelationByIdRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			apierror.InvalidJSON(w, r, "", err)
+			return
+		}
+
+		if len(req.NewProperties) == 0 {
+			apierror.MissingFields(w, r, "NewProperties")
+			return
+		}
+
+		if err := identifier.PropertyKeys(req.NewProperties); err != nil {
+			apierror.InvalidField(w, r, "NewProperties", err)
+			return
+		}
+
+		if registry != nil {
+			match, err := db.GetRelation(ctx, elementId)
+			if err != nil {
+				log.Error().Err(err).Str("elementId", elementId).Msg("Error consultando la relación")
+				apierror.StoreError(w, r, err, notFoundMessage(elementId))
+				return
+			}
+			if err := registry.CheckRelationUpdate(match.Relation.Type, req.NewProperties); err != nil {
+				apierror.InvalidField(w, r, "NewProperties", err)
+				return
+			}
+		}
+
+		relation, err := db.UpdateRelation(ctx, elementId, req.NewProperties)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error actualizando la relación")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.Relation(relation))
+	}
+}
+
+// NewDeleteRelationByIdHandler handles `DELETE /relation/{elementId}`.
+func NewDeleteRelationByIdHandler(db store.GraphStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		elementId := chi.URLParam(r, "elementId")
+
+		_, err := db.DeleteRelation(ctx, elementId)
+		if err != nil {
+			log.Error().Err(err).Str("elementId", elementId).Msg("Error al eliminar la relación")
+			apierror.StoreError(w, r, err, notFoundMessage(elementId))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(dto.DeletedDTO{DeletedCount: 1})
+	}
+}
+
+func notFoundMessage(elementId string) string {
+	return fmt.Sprintf("No relationship with element ID `%s` exists", elementId)
+}
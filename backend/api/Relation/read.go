@@ -1,35 +1,28 @@
 package relation
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
-type ReadNeo4JObject struct {
-	Category   string         `json:"Category"`
-	Properties map[string]any `json:"Properties"`
-}
-
 type ReadRelationRequest struct {
-	OriginNode      ReadNeo4JObject `json:"OriginNode"`
-	DestinationNode ReadNeo4JObject `json:"DestinationNode"`
-	Relation        ReadNeo4JObject `json:"Relation"`
+	OriginNode      utils.Neo4JObject `json:"OriginNode"`
+	DestinationNode utils.Neo4JObject `json:"DestinationNode"`
+	Relation        utils.Neo4JObject `json:"Relation"`
 }
 
-
-func NewReadRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewReadRelationHandler(db store.GraphStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		if r.Method != http.MethodGet {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			w.Write([]byte("405 Method Not Allowed - Use GET"))
+			apierror.MethodNotAllowed(w, r, http.MethodGet)
 			return
 		}
 
@@ -39,96 +32,53 @@ func NewReadRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
 		relation := queryParams.Get("Relation")
 
 		if origin == "" || destination == "" || relation == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("400 Bad Request - Missing required query parameters"))
+			apierror.MissingFields(w, r, "OriginNode", "DestinationNode", "Relation")
 			return
 		}
 
-		var originNode, destinationNode, relationObj ReadNeo4JObject
+		var originNode, destinationNode, relationObj utils.Neo4JObject
 		err := json.Unmarshal([]byte(origin), &originNode)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid `OriginNode`: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "OriginNode", err)
 			return
 		}
 
 		err = json.Unmarshal([]byte(destination), &destinationNode)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid `DestinationNode`: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "DestinationNode", err)
 			return
 		}
 
 		err = json.Unmarshal([]byte(relation), &relationObj)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid `Relation`: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "Relation", err)
 			return
 		}
 
-
-		b := &strings.Builder{}
-		b.WriteString("MATCH ")
-		originNode.appendAsNode(b, "n1") 
-		b.WriteString(" -")
-		relationObj.appendAsRelation(b, "r")
-		b.WriteString("-> ")
-		destinationNode.appendAsNode(b, "n2")
-		b.WriteString(" RETURN r, n1, n2 LIMIT 10")
-
-		query := b.String()
-		params := make(map[string]any)
-
-		for property, val := range originNode.Properties {
-			params["n1_"+property] = val
-		}
-
-		for property, val := range relationObj.Properties {
-			params["r_"+property] = val
-		}
-
-		for property, val := range destinationNode.Properties {
-			params["n2_"+property] = val
+		pattern, err := utils.FilterRelationPattern(&originNode, &destinationNode, &relationObj)
+		if err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
 		}
 
-		log.Info().Str("query", query).Msg("Ejecutando consulta de búsqueda en Neo4j...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		log.Info().Msg("Ejecutando consulta de búsqueda...")
+		matches, err := db.FindRelations(ctx, pattern, 10)
 
 		if err != nil {
-			log.Error().Err(err).Msg("Error consultando en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("500 Internal Server Error - Neo4j Query Error: %s", err.Error())))
+			log.Error().Err(err).Msg("Error consultando relaciones")
+			apierror.DBError(w, r, err)
 			return
 		}
 
-		if len(result.Records) == 0 {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 ERROR - No relationships found"))
+		if len(matches) == 0 {
+			apierror.NotFound(w, r, "No relationship matched the request")
 			return
 		}
 
-		var relationships []map[string]any
-		for _, record := range result.Records {
-			relData := map[string]any{}
-
-			if rel, ok := record.Get("r"); ok {
-				relData["relation"] = rel
-			}
-			if origin, ok := record.Get("n1"); ok {
-				relData["origin"] = origin
-			}
-			if destination, ok := record.Get("n2"); ok {
-				relData["destination"] = destination
-			}
-
-			relationships = append(relationships, relData)
-		}
-
-		response, err := json.Marshal(relationships)
+		response, err := json.Marshal(dto.RelationMatches(matches))
 		if err != nil {
 			log.Error().Err(err).Msg("Error codificando la respuesta")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
@@ -136,53 +86,3 @@ func NewReadRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
 		w.Write(response)
 	}
 }
-
-func (self *ReadNeo4JObject) appendAsNode(b *strings.Builder, queryId string) {
-	b.WriteRune('(')
-	b.WriteString(queryId)
-	b.WriteRune(':')
-	b.WriteString(self.Category)
-
-	if len(self.Properties) > 0 {
-		b.WriteString(" {")
-		i := 0
-		for property := range self.Properties {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(property)
-			b.WriteString(": $")
-			b.WriteString(queryId)
-			b.WriteString("_")
-			b.WriteString(property)
-			i++
-		}
-		b.WriteString("}")
-	}
-	b.WriteRune(')')
-}
-
-func (self *ReadNeo4JObject) appendAsRelation(b *strings.Builder, queryId string) {
-	b.WriteRune('[')
-	b.WriteString(queryId)
-	b.WriteRune(':')
-	b.WriteString(self.Category)
-
-	if len(self.Properties) > 0 {
-		b.WriteString(" {")
-		i := 0
-		for property := range self.Properties {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			b.WriteString(property)
-			b.WriteString(": $")
-			b.WriteString(queryId)
-			b.WriteString("_")
-			b.WriteString(property)
-			i++
-		}
-		b.WriteString("}")
-	}
-	b.WriteRune(']')
-}
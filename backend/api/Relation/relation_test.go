@@ -0,0 +1,145 @@
+package relation_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+const (
+	origin      = `{"Category": "Provider", "Properties": {"id": "PR1"}}`
+	destination = `{"Category": "Material", "Properties": {"id": "M1"}}`
+)
+
+// newRouter serves the relationship endpoints over a store with provider PR1
+// and material M1.
+func newRouter(t *testing.T) http.Handler {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	app := api.NewApi(memstore.New(), nil)
+
+	r := chi.NewRouter()
+	r.Post("/node", app.CreateNodeHandler)
+	r.Post("/relation", app.CreateRelationHandler)
+	r.Get("/relation", app.ReadRelationHandler)
+	r.Put("/relation", app.UpdateRelationHandler)
+	r.Delete("/relation", app.DeleteRelationHandler)
+	r.Get("/relation/{elementId}", app.ReadRelationByIdHandler)
+	r.Put("/relation/{elementId}", app.UpdateRelationByIdHandler)
+	r.Delete("/relation/{elementId}", app.DeleteRelationByIdHandler)
+
+	send(t, r, http.MethodPost, "/node", `{"NodeType": "Provider", "Properties": {"id": "PR1"}}`, http.StatusOK, nil)
+	send(t, r, http.MethodPost, "/node", `{"NodeType": "Material", "Properties": {"id": "M1"}}`, http.StatusOK, nil)
+	return r
+}
+
+// send makes a request and decodes the JSON response into out, when it isn't nil.
+func send(t *testing.T, router http.Handler, method string, target string, body string, wantStatus int, out any) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	if w.Code != wantStatus {
+		t.Fatalf("%s %s answered %d, want %d: %s", method, target, w.Code, wantStatus, w.Body)
+	}
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s answered invalid JSON: %v", method, target, err)
+		}
+	}
+}
+
+func pattern(relation string) string {
+	return `{"OriginNode": ` + origin + `, "DestinationNode": ` + destination + `, "Relation": ` + relation + `}`
+}
+
+func readQuery(relation string) string {
+	return "/relation?" + url.Values{"OriginNode": {origin}, "DestinationNode": {destination}, "Relation": {relation}}.Encode()
+}
+
+func TestRelationCRUD(t *testing.T) {
+	router := newRouter(t)
+
+	var created dto.RelationMatchDTO
+	send(t, router, http.MethodPost, "/relation", pattern(`{"Category": "PRODUCES", "Properties": {"cost": 10}}`), http.StatusCreated, &created)
+	if created.Relation.Type != "PRODUCES" || created.Origin.Properties["id"] != "PR1" || created.Destination.Properties["id"] != "M1" {
+		t.Fatalf("unexpected created relationship %+v", created)
+	}
+	if created.Relation.StartElementId != created.Origin.ElementId || created.Relation.EndElementId != created.Destination.ElementId {
+		t.Errorf("the relationship doesn't point from the origin to the destination: %+v", created)
+	}
+
+	var found []dto.RelationMatchDTO
+	send(t, router, http.MethodGet, readQuery(`{"Category": "PRODUCES"}`), "", http.StatusOK, &found)
+	if len(found) != 1 || found[0].Relation.ElementId != created.Relation.ElementId {
+		t.Fatalf("GET /relation returned %+v", found)
+	}
+
+	var updated dto.RelationDTO
+	send(t, router, http.MethodPut, "/relation", `{"OriginNode": `+origin+`, "DestinationNode": `+destination+`, "Relation": {"Category": "PRODUCES"}, "NewProperties": {"cost": 12}}`, http.StatusOK, &updated)
+	if updated.Properties["cost"] != 12.0 {
+		t.Errorf("PUT /relation didn't update the relationship: %+v", updated)
+	}
+
+	var match dto.RelationMatchDTO
+	send(t, router, http.MethodGet, "/relation/"+created.Relation.ElementId, "", http.StatusOK, &match)
+	if match.Relation.Properties["cost"] != 12.0 {
+		t.Errorf("GET /relation/{elementId} didn't return the update: %+v", match)
+	}
+
+	var updatedById dto.RelationDTO
+	send(t, router, http.MethodPut, "/relation/"+created.Relation.ElementId, `{"NewProperties": {"cost": null, "leadTime": 3}}`, http.StatusOK, &updatedById)
+	if _, hasCost := updatedById.Properties["cost"]; hasCost || updatedById.Properties["leadTime"] != 3.0 {
+		t.Errorf("PUT /relation/{elementId} didn't update the relationship: %+v", updatedById)
+	}
+
+	var deleted dto.DeletedDTO
+	send(t, router, http.MethodDelete, "/relation", pattern(`{"Category": "PRODUCES"}`), http.StatusOK, &deleted)
+	if deleted.DeletedCount != 1 {
+		t.Errorf("DELETE /relation deleted %d relationships, want 1", deleted.DeletedCount)
+	}
+	send(t, router, http.MethodGet, readQuery(`{"Category": "PRODUCES"}`), "", http.StatusNotFound, nil)
+	send(t, router, http.MethodGet, "/relation/"+created.Relation.ElementId, "", http.StatusNotFound, nil)
+}
+
+func TestDeleteRelationById(t *testing.T) {
+	router := newRouter(t)
+
+	var created dto.RelationMatchDTO
+	send(t, router, http.MethodPost, "/relation", pattern(`{"Category": "PRODUCES"}`), http.StatusCreated, &created)
+	send(t, router, http.MethodDelete, "/relation/"+created.Relation.ElementId, "", http.StatusOK, nil)
+	send(t, router, http.MethodDelete, "/relation/"+created.Relation.ElementId, "", http.StatusNotFound, nil)
+}
+
+func TestRelationValidation(t *testing.T) {
+	router := newRouter(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{"create without type", http.MethodPost, "/relation", pattern(`{"Category": ""}`), http.StatusBadRequest},
+		{"create with invalid type", http.MethodPost, "/relation", pattern(`{"Category": "BAD TYPE"}`), http.StatusBadRequest},
+		{"create between unknown nodes", http.MethodPost, "/relation",
+			`{"OriginNode": {"Category": "Provider", "Properties": {"id": "X"}}, "DestinationNode": ` + destination + `, "Relation": {"Category": "PRODUCES"}}`,
+			http.StatusNotFound},
+		{"read with invalid JSON", http.MethodGet, "/relation?OriginNode=%7B&DestinationNode=%7B%7D&Relation=%7B%7D", "", http.StatusBadRequest},
+		{"update unknown relationship", http.MethodPut, "/relation", `{"OriginNode": ` + origin + `, "DestinationNode": ` + destination + `, "Relation": {"Category": "NEEDS"}, "NewProperties": {"a": 1}}`, http.StatusNotFound},
+		{"delete unknown relationship", http.MethodDelete, "/relation", pattern(`{"Category": "NEEDS"}`), http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			send(t, router, test.method, test.target, test.body, test.wantStatus, nil)
+		})
+	}
+}
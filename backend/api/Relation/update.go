@@ -2,14 +2,15 @@ package relation
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	ut "github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,95 +21,58 @@ type reqBody struct {
 	NewProperties   map[string]any
 }
 
-func NewUpdateRelationHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewUpdateRelationHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		decoder := json.NewDecoder(r.Body)
 		var body reqBody
 
 		err := decoder.Decode(&body)
 		if err != nil {
-			msg := fmt.Sprintf("BAD REQUEST - INVALID BODY! `%s`", err.Error())
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(msg))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
-		// MATCH (n:$NodeType {$nodeName: $nodeValue}) -[r:$RelationType {$relName: $relValue}]-> (n2:$NodeType {$nodeName: $nodeValue})
-		// SET r.$NewProperty = $NewValue
-		// RETURN r
-		// LIMIT 1
-		b := &strings.Builder{}
-		b.WriteString("MATCH ")
-		body.OriginNode.AppendAsNeo4JMatch(b, []string{"(", ")"}, "n1")
-		b.WriteString(" -")
-		body.Relation.AppendAsNeo4JMatch(b, []string{"[", "]"}, "r")
-		b.WriteString("-> ")
-		body.DestinationNode.AppendAsNeo4JMatch(b, []string{"(", ")"}, "n2")
-
-		for propertyName := range body.NewProperties {
-			b.WriteString("SET ")
-			b.WriteString("r.")
-			b.WriteString(propertyName)
-			b.WriteString(" = ")
-			b.WriteString("$new_")
-			b.WriteString(propertyName)
-			b.WriteRune(' ')
-		}
-		b.WriteString(" RETURN r LIMIT 1")
-
-		query := b.String()
-		params := make(map[string]any)
-
-		for property, val := range body.OriginNode.Properties {
-			key := strings.Join([]string{"n1_", property}, "")
-			params[key] = val
-		}
-
-		for property, val := range body.Relation.Properties {
-			key := strings.Join([]string{"r_", property}, "")
-			params[key] = val
+		if err := ut.ValidateRelationPattern(&body.OriginNode, &body.DestinationNode, &body.Relation); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
 		}
 
-		for property, val := range body.DestinationNode.Properties {
-			key := strings.Join([]string{"n2_", property}, "")
-			params[key] = val
+		if err := identifier.PropertyKeys(body.NewProperties); err != nil {
+			apierror.InvalidField(w, r, "NewProperties", err)
+			return
 		}
 
-		for property, val := range body.NewProperties {
-			key := strings.Join([]string{"new_", property}, "")
-			params[key] = val
+		if err := registry.CheckRelationUpdate(body.Relation.Category, body.NewProperties); err != nil {
+			apierror.InvalidField(w, r, "NewProperties", err)
+			return
 		}
 
-		log.Info().Str("query", query).Msg("Querying DB...")
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		relations, err := db.UpdateRelations(ctx, ut.ToRelationPattern(&body.OriginNode, &body.DestinationNode, &body.Relation), body.NewProperties, 0)
 
 		if err != nil {
 			log.Error().Err(err).Msg("Error querying DB!")
-			w.WriteHeader(http.StatusInternalServerError)
-			msg := fmt.Sprintf("INTERNAL SERVER ERROR - QUERY ERROR `%s`", err.Error())
-			w.Write([]byte(msg))
+			apierror.DBError(w, r, err)
 			return
 		}
-		nodeCount := len(result.Records)
-		log.Info().Int("recordCount", nodeCount).Msg("Done!")
+		relationCount := len(relations)
+		log.Info().Int("recordCount", relationCount).Msg("Done!")
 
-		if nodeCount == 0 {
+		if relationCount == 0 {
 			log.Error().Err(err).Msg("No row found!")
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 ERROR - NOT FOUND"))
+			apierror.NotFound(w, r, "No relationship matched the request")
 			return
 		}
 
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
 
-		err = enc.Encode(result.Records[0])
+		relation := dto.Relation(relations[0])
+		err = enc.Encode(relation)
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("row", relation).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
@@ -2,14 +2,15 @@ package relationproperties
 
 import (
 	"bytes"
-	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/dto"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,99 +21,52 @@ type RemoveRelationPropertiesRequest struct {
 	Properties      []string
 }
 
-func NewRemoveRelationPropertiesHandler(client *neo4j.DriverWithContext) http.HandlerFunc {
+func NewRemoveRelationPropertiesHandler(db store.GraphStore, registry *schema.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 
 		var req RemoveRelationPropertiesRequest
 		err := json.NewDecoder(r.Body).Decode(&req)
 		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte(fmt.Sprintf("400 Bad Request - Invalid JSON: %s", err.Error())))
+			apierror.InvalidJSON(w, r, "", err)
 			return
 		}
 
 		if req.OriginNode.Category == "" || req.DestinationNode.Category == "" || req.Relation.Category == "" {
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("400 Bad Request - `OriginNode`, `DestinationNode`, y `Relation` son requeridos"))
+			apierror.MissingFields(w, r, "OriginNode", "DestinationNode", "Relation")
 			return
 		}
 
-		var queryBuilder strings.Builder
-		queryBuilder.WriteString("MATCH (n1:")
-		queryBuilder.WriteString(req.OriginNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i := 0
-		for key := range req.OriginNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n1_")
-			queryBuilder.WriteString(key)
-			i++
-		}
-		queryBuilder.WriteString("})-[r:")
-		queryBuilder.WriteString(req.Relation.Category)
-		queryBuilder.WriteString("]->(n2:")
-		queryBuilder.WriteString(req.DestinationNode.Category)
-		queryBuilder.WriteString(" {")
-
-		i = 0
-		for key := range req.DestinationNode.Properties {
-			if i > 0 {
-				queryBuilder.WriteString(", ")
-			}
-			queryBuilder.WriteString(key)
-			queryBuilder.WriteString(": $n2_")
-			queryBuilder.WriteString(key)
-			i++
+		if err := utils.ValidateRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation); err != nil {
+			apierror.InvalidField(w, r, "", err)
+			return
 		}
-		queryBuilder.WriteString("}) ")
 
-		for _, prop := range req.Properties {
-			queryBuilder.WriteString(" REMOVE ")
-			queryBuilder.WriteString("r.")
-			queryBuilder.WriteString(prop)
+		if err := identifier.PropertyKeyList(req.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
 		}
 
-		queryBuilder.WriteString(" RETURN r")
-
-		query := queryBuilder.String()
-
-		params := make(map[string]any)
-
-		for property, val := range req.OriginNode.Properties {
-			params["n1_"+property] = val
-		}
-		for property, val := range req.Relation.Properties {
-			params["r_"+property] = val
-		}
-		for property, val := range req.DestinationNode.Properties {
-			params["n2_"+property] = val
+		if err := registry.CheckRelationRemoval(req.Relation.Category, req.Properties); err != nil {
+			apierror.InvalidField(w, r, "Properties", err)
+			return
 		}
 
-		log.Info().Str("query", query).Msg("Eliminando propiedades de nodos...")
-		log.Debug().Msg(fmt.Sprintf("%v", params))
-
-		result, err := neo4j.ExecuteQuery(ctx, *client, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase("neo4j"))
+		log.Info().Msg("Eliminando propiedades de relaciones...")
+		relations, err := db.RemoveRelationProperties(ctx, utils.ToRelationPattern(&req.OriginNode, &req.DestinationNode, &req.Relation), req.Properties)
 
 		if err != nil {
-			log.Error().Err(err).Msg("Error al eliminar propiedades en Neo4j")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(fmt.Sprintf("500 Internal Server Error - Neo4j Delete Error: %s", err.Error())))
+			log.Error().Err(err).Msg("Error al eliminar propiedades")
+			apierror.DBError(w, r, err)
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
 		var buff bytes.Buffer
 		enc := json.NewEncoder(&buff)
-		err = enc.Encode(result.Records)
+		err = enc.Encode(dto.Relations(relations))
 		if err != nil {
-			log.Error().Err(err).Interface("row", result.Records[0]).Msg("Error encoding row!")
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("500 ERROR - INTERNAL SERVER ERROR"))
+			log.Error().Err(err).Interface("row", relations).Msg("Error encoding row!")
+			apierror.Internal(w, r, "The response couldn't be encoded")
 			return
 		}
 
@@ -3,17 +3,23 @@ package api
 import (
 	"net/http"
 
+	admin "github.com/ElrohirGT/Proyecto1_DB2/api/Admin"
+	batch "github.com/ElrohirGT/Proyecto1_DB2/api/Batch"
+	export "github.com/ElrohirGT/Proyecto1_DB2/api/Export"
 	functionalrequirements "github.com/ElrohirGT/Proyecto1_DB2/api/FunctionalRequirements"
+	importer "github.com/ElrohirGT/Proyecto1_DB2/api/Import"
+	introspection "github.com/ElrohirGT/Proyecto1_DB2/api/Introspection"
 	node "github.com/ElrohirGT/Proyecto1_DB2/api/Node"
 	properties "github.com/ElrohirGT/Proyecto1_DB2/api/Properties"
 	relation "github.com/ElrohirGT/Proyecto1_DB2/api/Relation"
 	relationproperties "github.com/ElrohirGT/Proyecto1_DB2/api/RelationProperties"
 	"github.com/ElrohirGT/Proyecto1_DB2/api/health"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 )
 
 type Api struct {
-	dbClient *neo4j.DriverWithContext
+	db store.GraphStore
 
 	// Handlers: endpoint functions
 	CheckHealthHandler http.HandlerFunc
@@ -22,16 +28,25 @@ type Api struct {
 	// CRUD
 	CreateNodeHandler      http.HandlerFunc
 	ReadNodeHandler        http.HandlerFunc
+	ListNodesHandler       http.HandlerFunc
 	UpdateNodeHandler      http.HandlerFunc
 	DeleteNodeHandler      http.HandlerFunc
 	DeleteManyNodesHandler http.HandlerFunc
 
+	ReadNodeByIdHandler   http.HandlerFunc
+	UpdateNodeByIdHandler http.HandlerFunc
+	DeleteNodeByIdHandler http.HandlerFunc
+
 	CreateRelationHandler      http.HandlerFunc
 	ReadRelationHandler        http.HandlerFunc
 	UpdateRelationHandler      http.HandlerFunc
 	DeleteRelationHandler      http.HandlerFunc
 	DeleteManyRelationsHandler http.HandlerFunc
 
+	ReadRelationByIdHandler   http.HandlerFunc
+	UpdateRelationByIdHandler http.HandlerFunc
+	DeleteRelationByIdHandler http.HandlerFunc
+
 	// RELATION PROPERTIES
 	CreateRelationPropertiesHandler http.HandlerFunc
 	RemoveRelationPropertiesHandler http.HandlerFunc
@@ -40,40 +55,97 @@ type Api struct {
 	UpdatePropertiesHandler http.HandlerFunc
 	DeletePropertiesHandler http.HandlerFunc
 
+	// BATCH
+	BatchHandler http.HandlerFunc
+
+	// IMPORT
+	ImportNodesHandler     http.HandlerFunc
+	ImportRelationsHandler http.HandlerFunc
+
+	// EXPORT
+	ExportHandler http.HandlerFunc
+
+	// SCHEMA
+	SchemaHandler http.HandlerFunc
+
+	// ADMIN
+	ListConstraintsHandler  http.HandlerFunc
+	CreateConstraintHandler http.HandlerFunc
+	DropConstraintHandler   http.HandlerFunc
+	ListIndexesHandler      http.HandlerFunc
+	CreateIndexHandler      http.HandlerFunc
+	DropIndexHandler        http.HandlerFunc
+
 	// FUNC REQUIREMENTS
-	GetProductHistoryHandler http.HandlerFunc
-	GetStatisticsHandler     http.HandlerFunc
+	GetProductHistoryHandler  http.HandlerFunc
+	GetBillOfMaterialsHandler http.HandlerFunc
+	GetImpactHandler          http.HandlerFunc
+	GetPathsHandler           http.HandlerFunc
+	GetSupplyRisksHandler     http.HandlerFunc
+	GetStatisticsHandler      http.HandlerFunc
+	GetSalesHandler           http.HandlerFunc
 }
 
 func NewApi(
-	mongoClient *neo4j.DriverWithContext,
+	db store.GraphStore,
+	registry *schema.Registry,
 ) *Api {
 
 	return &Api{
-		dbClient: mongoClient,
+		db: db,
 
 		CheckHealthHandler: health.CheckHealthHandler,
 
-		CreateNodeHandler:      node.NewCreateNodeHandler(mongoClient),
-		ReadNodeHandler:        node.NewReadNodeHandler(mongoClient),
-		UpdateNodeHandler:      node.NewUpdateNodeHandler(mongoClient),
-		DeleteNodeHandler:      node.NewDeleteNodeHandler(mongoClient),
-		DeleteManyNodesHandler: node.NewDeleteManyNodesHandler(mongoClient),
+		CreateNodeHandler:      node.NewCreateNodeHandler(db, registry),
+		ReadNodeHandler:        node.NewReadNodeHandler(db),
+		ListNodesHandler:       node.NewListNodesHandler(db),
+		UpdateNodeHandler:      node.NewUpdateNodeHandler(db, registry),
+		DeleteNodeHandler:      node.NewDeleteNodeHandler(db),
+		DeleteManyNodesHandler: node.NewDeleteManyNodesHandler(db),
+
+		ReadNodeByIdHandler:   node.NewReadNodeByIdHandler(db),
+		UpdateNodeByIdHandler: node.NewUpdateNodeByIdHandler(db, registry),
+		DeleteNodeByIdHandler: node.NewDeleteNodeByIdHandler(db),
+
+		CreateRelationHandler:      relation.NewCreateRelationHandler(db, registry),
+		ReadRelationHandler:        relation.NewReadRelationHandler(db),
+		UpdateRelationHandler:      relation.NewUpdateRelationHandler(db, registry),
+		DeleteRelationHandler:      relation.NewDeleteRelationHandler(db),
+		DeleteManyRelationsHandler: relation.NewDeleteManyRelationsHandler(db),
+
+		ReadRelationByIdHandler:   relation.NewReadRelationByIdHandler(db),
+		UpdateRelationByIdHandler: relation.NewUpdateRelationByIdHandler(db, registry),
+		DeleteRelationByIdHandler: relation.NewDeleteRelationByIdHandler(db),
+
+		CreateRelationPropertiesHandler: relationproperties.NewCreateRelationPropertiesHandler(db, registry),
+		RemoveRelationPropertiesHandler: relationproperties.NewRemoveRelationPropertiesHandler(db, registry),
+
+		UpdatePropertiesHandler: properties.NewUpdatePropertiesHandler(db, registry),
+		DeletePropertiesHandler: properties.NewDeleteNodePropertiesHandler(db, registry),
+
+		BatchHandler: batch.NewBatchHandler(db, registry),
+
+		ImportNodesHandler:     importer.NewImportNodesHandler(db, registry),
+		ImportRelationsHandler: importer.NewImportRelationsHandler(db, registry),
 
-		CreateRelationHandler:      relation.NewCreateRelationHandler(mongoClient),
-		ReadRelationHandler:        relation.NewReadRelationHandler(mongoClient),
-		UpdateRelationHandler:      relation.NewUpdateRelationHandler(mongoClient),
-		DeleteRelationHandler:      relation.NewDeleteRelationHandler(mongoClient),
-		DeleteManyRelationsHandler: relation.NewDeleteManyRelationsHandler(mongoClient),
+		ExportHandler: export.NewExportHandler(db),
 
-		CreateRelationPropertiesHandler: relationproperties.NewCreateRelationPropertiesHandler(mongoClient),
-		RemoveRelationPropertiesHandler: relationproperties.NewRemoveRelationPropertiesHandler(mongoClient),
+		SchemaHandler: introspection.NewSchemaHandler(db),
 
-		UpdatePropertiesHandler: properties.NewUpdatePropertiesHandler(mongoClient),
-		DeletePropertiesHandler: properties.NewDeleteNodePropertiesHandler(mongoClient),
+		ListConstraintsHandler:  admin.NewListConstraintsHandler(db),
+		CreateConstraintHandler: admin.NewCreateConstraintHandler(db),
+		DropConstraintHandler:   admin.NewDropConstraintHandler(db),
+		ListIndexesHandler:      admin.NewListIndexesHandler(db),
+		CreateIndexHandler:      admin.NewCreateIndexHandler(db),
+		DropIndexHandler:        admin.NewDropIndexHandler(db),
 
-		GetProductHistoryHandler: functionalrequirements.NewGetHistoryHandler(mongoClient),
-		GetStatisticsHandler:     functionalrequirements.GetStatisticsHandler(mongoClient),
+		GetProductHistoryHandler:  functionalrequirements.NewGetHistoryHandler(db),
+		GetBillOfMaterialsHandler: functionalrequirements.NewGetBillOfMaterialsHandler(db),
+		GetImpactHandler:          functionalrequirements.NewGetImpactHandler(db),
+		GetPathsHandler:           functionalrequirements.NewGetPathsHandler(db),
+		GetSupplyRisksHandler:     functionalrequirements.NewGetSupplyRisksHandler(db),
+		GetStatisticsHandler:      functionalrequirements.GetStatisticsHandler(db),
+		GetSalesHandler:           functionalrequirements.NewGetSalesHandler(db),
 	}
 
 }
@@ -0,0 +1,170 @@
+// Package apierror writes the JSON body every endpoint answers with when a
+// request fails:
+//
+//	{"code": "MISSING_FIELD", "message": "...", "details": {...}, "requestId": "..."}
+//
+// `code` is stable and meant to be branched on by clients, `message` is for humans.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/api/utils"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// Code is a machine readable error identifier.
+type Code string
+
+const (
+	CodeInvalidJSON      Code = "INVALID_JSON"
+	CodeMissingField     Code = "MISSING_FIELD"
+	CodeInvalidField     Code = "INVALID_FIELD"
+	CodeSchemaViolation  Code = "SCHEMA_VIOLATION"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeDBError          Code = "DB_ERROR"
+	CodeUnsupported      Code = "UNSUPPORTED"
+	CodeTimeout          Code = "TIMEOUT"
+	CodeInternalError    Code = "INTERNAL_ERROR"
+)
+
+// Response is the body of every error response.
+type Response struct {
+	Code      Code   `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details"`
+	RequestId string `json:"requestId"`
+}
+
+// FieldDetails points to the request field that caused the error.
+type FieldDetails struct {
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Write sends an error response with the given status.
+func Write(w http.ResponseWriter, r *http.Request, status int, code Code, message string, details any) {
+	response := Response{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestId: middleware.GetReqID(r.Context()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Error().Err(err).Interface("response", response).Msg("Error encoding error response!")
+	}
+}
+
+// InvalidJSON reports a request body, or a JSON query parameter when field
+// isn't empty, that couldn't be decoded.
+func InvalidJSON(w http.ResponseWriter, r *http.Request, field string, err error) {
+	message := "The request body is not valid JSON"
+	if field != "" {
+		message = fmt.Sprintf("`%s` is not valid JSON", field)
+	}
+	Write(w, r, http.StatusBadRequest, CodeInvalidJSON, message, FieldDetails{Field: field, Reason: err.Error()})
+}
+
+// MissingFields reports required request fields that weren't sent.
+func MissingFields(w http.ResponseWriter, r *http.Request, fields ...string) {
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		quoted = append(quoted, "`"+field+"`")
+	}
+	message := fmt.Sprintf("%s are required", strings.Join(quoted, ", "))
+	if len(fields) == 1 {
+		message = fmt.Sprintf("%s is required", quoted[0])
+	}
+	Write(w, r, http.StatusBadRequest, CodeMissingField, message, map[string][]string{"fields": fields})
+}
+
+// SchemaDetails lists why a request field breaks the schema registry.
+type SchemaDetails struct {
+	Field      string             `json:"field,omitempty"`
+	Subject    string             `json:"subject"`
+	Violations []schema.Violation `json:"violations"`
+}
+
+// InvalidField reports a request field with an invalid value. If err is a
+// *utils.FieldError its field is used instead of the given one, and if it's a
+// *schema.Error it's reported with SchemaViolation.
+func InvalidField(w http.ResponseWriter, r *http.Request, field string, err error) {
+	var fieldErr *utils.FieldError
+	if errors.As(err, &fieldErr) {
+		field, err = fieldErr.Field, fieldErr.Err
+	}
+	var schemaErr *schema.Error
+	if errors.As(err, &schemaErr) {
+		SchemaViolation(w, r, field, schemaErr)
+		return
+	}
+	message := "The request is invalid"
+	if field != "" {
+		message = fmt.Sprintf("`%s` is invalid", field)
+	}
+	Write(w, r, http.StatusBadRequest, CodeInvalidField, message, FieldDetails{Field: field, Reason: err.Error()})
+}
+
+// SchemaViolation reports a node or relationship that breaks the schema registry.
+func SchemaViolation(w http.ResponseWriter, r *http.Request, field string, err *schema.Error) {
+	message := fmt.Sprintf("`%s` doesn't match the schema", err.Subject)
+	details := SchemaDetails{Field: field, Subject: err.Subject, Violations: err.Violations}
+	Write(w, r, http.StatusUnprocessableEntity, CodeSchemaViolation, message, details)
+}
+
+// NotFound reports that nothing in the graph matched the request.
+func NotFound(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusNotFound, CodeNotFound, message, nil)
+}
+
+// MethodNotAllowed reports a request made with the wrong HTTP method.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed string) {
+	w.Header().Set("Allow", allowed)
+	Write(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, fmt.Sprintf("Use %s", allowed), nil)
+}
+
+// DBError reports a failed query.
+func DBError(w http.ResponseWriter, r *http.Request, err error) {
+	Write(w, r, http.StatusInternalServerError, CodeDBError, "The database query failed", FieldDetails{Reason: err.Error()})
+}
+
+// StoreError reports an error returned by the graph store, store.ErrNotFound
+// is reported as NOT_FOUND with the given message and store.ErrUnsupported as
+// UNSUPPORTED.
+func StoreError(w http.ResponseWriter, r *http.Request, err error, notFound string) {
+	if errors.Is(err, store.ErrNotFound) {
+		NotFound(w, r, notFound)
+		return
+	}
+	if errors.Is(err, store.ErrUnsupported) {
+		Unsupported(w, r)
+		return
+	}
+	DBError(w, r, err)
+}
+
+// Unsupported reports a request the configured graph store can't perform.
+func Unsupported(w http.ResponseWriter, r *http.Request) {
+	Write(w, r, http.StatusNotImplemented, CodeUnsupported, "The configured graph store doesn't support this request", nil)
+}
+
+// Timeout reports a request that didn't finish before its deadline.
+func Timeout(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusGatewayTimeout, CodeTimeout, message, nil)
+}
+
+// Internal reports any other server side failure.
+func Internal(w http.ResponseWriter, r *http.Request, message string) {
+	Write(w, r, http.StatusInternalServerError, CodeInternalError, message, nil)
+}
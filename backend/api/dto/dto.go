@@ -0,0 +1,307 @@
+// Package dto defines the JSON bodies the API answers with.
+//
+// Handlers must convert store values with these helpers instead of encoding
+// them directly, so the response format doesn't depend on the struct layout of
+// the Neo4j driver.
+package dto
+
+import (
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// NodeDTO is a node of the graph.
+type NodeDTO struct {
+	ElementId  string         `json:"elementId"`
+	Labels     []string       `json:"labels"`
+	Properties map[string]any `json:"properties"`
+}
+
+// RelationDTO is a relationship of the graph, its endpoints are referenced by element ID.
+type RelationDTO struct {
+	ElementId      string         `json:"elementId"`
+	Type           string         `json:"type"`
+	Properties     map[string]any `json:"properties"`
+	StartElementId string         `json:"startElementId"`
+	EndElementId   string         `json:"endElementId"`
+}
+
+// RelationMatchDTO is a relationship together with the nodes it connects.
+type RelationMatchDTO struct {
+	Relation    RelationDTO `json:"relation"`
+	Origin      NodeDTO     `json:"origin"`
+	Destination NodeDTO     `json:"destination"`
+}
+
+// NodeUpdateDTO is a node before and after its properties were changed.
+type NodeUpdateDTO struct {
+	Before NodeDTO `json:"before"`
+	After  NodeDTO `json:"after"`
+}
+
+// NodeMergeDTO is the result of an upsert, created is false when the node already existed.
+type NodeMergeDTO struct {
+	Node    NodeDTO `json:"node"`
+	Created bool    `json:"created"`
+}
+
+// NodePageDTO is a page of a node listing.
+type NodePageDTO struct {
+	Nodes []NodeDTO `json:"nodes"`
+	Total int64     `json:"total"`
+	Skip  int       `json:"skip"`
+	Limit int       `json:"limit"`
+}
+
+// DeletedDTO reports how many entities a delete removed.
+type DeletedDTO struct {
+	DeletedCount int `json:"deletedCount"`
+}
+
+// DryRunDTO reports what a destructive request would affect, without applying it.
+type DryRunDTO[T any] struct {
+	DryRun   bool  `json:"dryRun"`
+	Affected int64 `json:"affected"`
+	Sample   []T   `json:"sample"`
+}
+
+// BatchResultDTO is the outcome of one operation of a batch, it has the node
+// or the relationship the operation touched.
+type BatchResultDTO struct {
+	Op       string       `json:"op"`
+	Ref      string       `json:"ref,omitempty"`
+	Node     *NodeDTO     `json:"node,omitempty"`
+	Relation *RelationDTO `json:"relation,omitempty"`
+}
+
+// BatchDTO has the results of every operation of a committed batch, in order.
+type BatchDTO struct {
+	Results []BatchResultDTO `json:"results"`
+}
+
+// ImportErrorDTO tells why the row at Line wasn't imported.
+type ImportErrorDTO struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportReportDTO is the outcome of a bulk import. Errors lists at most a
+// limited amount of failed rows, errorsTruncated tells if some were left out.
+type ImportReportDTO struct {
+	Rows            int              `json:"rows"`
+	Imported        int              `json:"imported"`
+	Failed          int              `json:"failed"`
+	Errors          []ImportErrorDTO `json:"errors"`
+	ErrorsTruncated bool             `json:"errorsTruncated"`
+}
+
+// ConstraintDTO is a database constraint.
+type ConstraintDTO struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	EntityType    string   `json:"entityType"`
+	LabelsOrTypes []string `json:"labelsOrTypes"`
+	Properties    []string `json:"properties"`
+}
+
+// IndexDTO is a database index, owningConstraint is set when a constraint is backed by it.
+type IndexDTO struct {
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	EntityType       string   `json:"entityType"`
+	LabelsOrTypes    []string `json:"labelsOrTypes"`
+	Properties       []string `json:"properties"`
+	State            string   `json:"state"`
+	OwningConstraint string   `json:"owningConstraint,omitempty"`
+}
+
+// SchemaChangeDTO names a created constraint or index, created is false when it already existed.
+type SchemaChangeDTO struct {
+	Name    string `json:"name"`
+	Created bool   `json:"created"`
+}
+
+// GraphSchemaDTO describes the labels and relationship types of the graph.
+type GraphSchemaDTO struct {
+	Labels            []LabelSummaryDTO    `json:"labels"`
+	RelationshipTypes []RelationSummaryDTO `json:"relationshipTypes"`
+	SampleSize        int                  `json:"sampleSize"`
+}
+
+// LabelSummaryDTO describes the nodes with a label, sampled tells how many of
+// them were read to infer the properties.
+type LabelSummaryDTO struct {
+	Label      string               `json:"label"`
+	Count      int64                `json:"count"`
+	Sampled    int                  `json:"sampled"`
+	Properties []PropertySummaryDTO `json:"properties"`
+}
+
+// RelationSummaryDTO describes the relationships of a type.
+type RelationSummaryDTO struct {
+	Type       string               `json:"type"`
+	Count      int64                `json:"count"`
+	Endpoints  []LabelPairDTO       `json:"endpoints"`
+	Sampled    int                  `json:"sampled"`
+	Properties []PropertySummaryDTO `json:"properties"`
+}
+
+type LabelPairDTO struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// PropertySummaryDTO is a property key, the types it was seen with and in how
+// many sampled elements.
+type PropertySummaryDTO struct {
+	Key         string   `json:"key"`
+	Types       []string `json:"types"`
+	Occurrences int      `json:"occurrences"`
+}
+
+func NodePreview(preview store.NodePreview) DryRunDTO[NodeDTO] {
+	return DryRunDTO[NodeDTO]{DryRun: true, Affected: preview.Affected, Sample: Nodes(preview.Sample)}
+}
+
+func RelationPreview(preview store.RelationPreview) DryRunDTO[RelationMatchDTO] {
+	return DryRunDTO[RelationMatchDTO]{DryRun: true, Affected: preview.Affected, Sample: RelationMatches(preview.Sample)}
+}
+
+func Node(node store.Node) NodeDTO {
+	labels := node.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	return NodeDTO{ElementId: node.ElementId, Labels: labels, Properties: properties(node.Props)}
+}
+
+func Nodes(nodes []store.Node) []NodeDTO {
+	return convert(nodes, Node)
+}
+
+func Relation(relation store.Relationship) RelationDTO {
+	return RelationDTO{
+		ElementId:      relation.ElementId,
+		Type:           relation.Type,
+		Properties:     properties(relation.Props),
+		StartElementId: relation.StartElementId,
+		EndElementId:   relation.EndElementId,
+	}
+}
+
+func Relations(relations []store.Relationship) []RelationDTO {
+	return convert(relations, Relation)
+}
+
+func RelationMatch(match store.RelationMatch) RelationMatchDTO {
+	return RelationMatchDTO{
+		Relation:    Relation(match.Relation),
+		Origin:      Node(match.Origin),
+		Destination: Node(match.Destination),
+	}
+}
+
+func RelationMatches(matches []store.RelationMatch) []RelationMatchDTO {
+	return convert(matches, RelationMatch)
+}
+
+func NodeMerge(merge store.NodeMerge) NodeMergeDTO {
+	return NodeMergeDTO{Node: Node(merge.Node), Created: merge.Created}
+}
+
+func NodeUpdate(update store.NodeUpdate) NodeUpdateDTO {
+	before := Node(update.Node)
+	before.Properties = properties(update.Before)
+	after := Node(update.Node)
+	after.Properties = properties(update.After)
+	return NodeUpdateDTO{Before: before, After: after}
+}
+
+func Constraints(constraints []store.Constraint) []ConstraintDTO {
+	return convert(constraints, func(c store.Constraint) ConstraintDTO {
+		return ConstraintDTO(c)
+	})
+}
+
+func Indexes(indexes []store.Index) []IndexDTO {
+	return convert(indexes, func(i store.Index) IndexDTO {
+		return IndexDTO(i)
+	})
+}
+
+func SchemaChange(change store.SchemaChange) SchemaChangeDTO {
+	return SchemaChangeDTO(change)
+}
+
+func GraphSchema(schema store.GraphSchema, sampleSize int) GraphSchemaDTO {
+	properties := func(summaries []store.PropertySummary) []PropertySummaryDTO {
+		return convert(summaries, func(p store.PropertySummary) PropertySummaryDTO {
+			return PropertySummaryDTO(p)
+		})
+	}
+
+	return GraphSchemaDTO{
+		Labels: convert(schema.Labels, func(l store.LabelSummary) LabelSummaryDTO {
+			return LabelSummaryDTO{Label: l.Label, Count: l.Count, Sampled: l.Sampled, Properties: properties(l.Properties)}
+		}),
+		RelationshipTypes: convert(schema.Relations, func(r store.RelationSummary) RelationSummaryDTO {
+			return RelationSummaryDTO{
+				Type:       r.Type,
+				Count:      r.Count,
+				Endpoints:  convert(r.Endpoints, func(p store.LabelPair) LabelPairDTO { return LabelPairDTO(p) }),
+				Sampled:    r.Sampled,
+				Properties: properties(r.Properties),
+			}
+		}),
+		SampleSize: sampleSize,
+	}
+}
+
+// properties makes sure a property map is encoded as `{}` instead of `null`,
+// its values are converted with propertyValue.
+func properties(props map[string]any) map[string]any {
+	converted := make(map[string]any, len(props))
+	for key, value := range props {
+		converted[key] = propertyValue(value)
+	}
+	return converted
+}
+
+// propertyValue converts the driver types that would be encoded by their
+// struct layout. Temporal values are written as ISO-8601 strings and points as
+// `{"srid", "x", "y", "z"}` objects, lists are converted item by item.
+func propertyValue(value any) any {
+	switch v := value.(type) {
+	case dbtype.Date:
+		return v.String()
+	case dbtype.LocalTime:
+		return v.String()
+	case dbtype.Time:
+		return v.String()
+	case dbtype.LocalDateTime:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case dbtype.Duration:
+		return v.String()
+	case dbtype.Point2D:
+		return map[string]any{"srid": v.SpatialRefId, "x": v.X, "y": v.Y}
+	case dbtype.Point3D:
+		return map[string]any{"srid": v.SpatialRefId, "x": v.X, "y": v.Y, "z": v.Z}
+	case []any:
+		return convert(v, propertyValue)
+	}
+	return value
+}
+
+// convert maps every item, a nil slice is converted into an empty one so it's
+// encoded as `[]` instead of `null`.
+func convert[T, D any](items []T, f func(T) D) []D {
+	converted := make([]D, 0, len(items))
+	for _, item := range items {
+		converted = append(converted, f(item))
+	}
+	return converted
+}
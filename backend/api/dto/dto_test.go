@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestPropertyEncoding(t *testing.T) {
+	utc := func(hour int) time.Time {
+		return time.Date(2024, time.March, 9, hour, 30, 15, 500_000_000, time.UTC)
+	}
+	offset := time.FixedZone("", -6*60*60)
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"date", dbtype.Date(time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC)), `"2024-03-09"`},
+		{"local time", dbtype.LocalTime(utc(14)), `"14:30:15.5"`},
+		{"time", dbtype.Time(time.Date(0, 1, 1, 14, 30, 15, 0, offset)), `"14:30:15-06:00"`},
+		{"local datetime", dbtype.LocalDateTime(utc(14)), `"2024-03-09T14:30:15.5"`},
+		{"datetime", time.Date(2024, time.March, 9, 14, 30, 15, 0, offset), `"2024-03-09T14:30:15-06:00"`},
+		{"duration", dbtype.Duration{Months: 14, Days: 3, Seconds: 90, Nanos: 0}, `"P14M3DT90S"`},
+		{"point 2D", dbtype.Point2D{X: -90.5, Y: 14.6, SpatialRefId: 4326}, `{"srid":4326,"x":-90.5,"y":14.6}`},
+		{"point 3D", dbtype.Point3D{X: 1, Y: 2, Z: 3, SpatialRefId: 9157}, `{"srid":9157,"x":1,"y":2,"z":3}`},
+		{"list of dates", []any{dbtype.Date(utc(0)), dbtype.Date(utc(0).AddDate(0, 0, 1))}, `["2024-03-09","2024-03-10"]`},
+		{"string", "Silla", `"Silla"`},
+		{"integer", int64(250), `250`},
+		{"list of numbers", []any{int64(1), 2.5}, `[1,2.5]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node := Node(store.Node{ElementId: "4:test:1", Props: map[string]any{"value": test.value}})
+			relation := Relation(store.Relationship{ElementId: "5:test:1", Type: "PRODUCES", Props: map[string]any{"value": test.value}})
+
+			for _, encoded := range []any{node.Properties, relation.Properties} {
+				got, err := json.Marshal(encoded)
+				if err != nil {
+					t.Fatalf("the properties couldn't be encoded: %v", err)
+				}
+				if want := `{"value":` + test.want + `}`; string(got) != want {
+					t.Errorf("got %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNilProperties(t *testing.T) {
+	got, err := json.Marshal(Node(store.Node{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"elementId":"","labels":[],"properties":{}}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
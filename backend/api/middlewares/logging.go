@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog/log"
 )
 
@@ -19,6 +20,7 @@ func Logging(next http.Handler) http.Handler {
 
 		// Log the request details using zerolog
 		log.Debug().
+			Str("requestId", middleware.GetReqID(r.Context())).
 			Str("method", r.Method).
 			Str("route", r.URL.Path).
 			Int("status", wrappedWriter.statusCode).
@@ -1,6 +1,12 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
 
 type Neo4JObjectType = string
 type Neo4JObjectProperties = map[string]any
@@ -10,35 +16,87 @@ type Neo4JObject struct {
 	Properties Neo4JObjectProperties
 }
 
-func (self *Neo4JObject) AppendAsNeo4JMatch(b *strings.Builder, limits []string, queryId string) {
-	if len(limits) != 2 {
-		panic("There should only be two limits! Example: []string {\"[\", \"]\"}")
+// FieldError tells which request field failed validation.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("`%s`: %s", e.Field, e.Err.Error())
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the category and every property key of the object.
+// `kind` tells if the category is a node label or a relationship type and
+// `field` is the name of the request field, used to build the error message.
+func (self *Neo4JObject) Validate(field string, kind identifier.Kind) error {
+	if err := identifier.Validate(kind, self.Category); err != nil {
+		return &FieldError{Field: field + ".Category", Err: err}
+	}
+	if err := identifier.PropertyKeys(self.Properties); err != nil {
+		return &FieldError{Field: field + ".Properties", Err: err}
 	}
+	return nil
+}
 
-	b.WriteString(limits[0])
-	b.WriteString(queryId)
-	b.WriteRune(':')
-	b.WriteString(self.Category)
+// Filter validates the object as a filter, where `Properties` may use the
+// operators of the filter package, and converts it into the store representation.
+func (self *Neo4JObject) Filter(field string, kind identifier.Kind) (store.Object, error) {
+	if err := identifier.Validate(kind, self.Category); err != nil {
+		return store.Object{}, &FieldError{Field: field + ".Category", Err: err}
+	}
+	equal, where, err := filter.Parse(self.Properties)
+	if err != nil {
+		return store.Object{}, &FieldError{Field: field + ".Properties", Err: err}
+	}
+	return store.Object{Category: self.Category, Properties: equal, Where: where}, nil
+}
+
+// ToStore converts the object into the store representation.
+func (self *Neo4JObject) ToStore() store.Object {
+	return store.Object{Category: self.Category, Properties: self.Properties}
+}
 
-	propertiesCount := len(self.Properties)
-	if propertiesCount > 0 {
-		b.WriteString(" {")
+// ToRelationPattern converts the objects of an `(origin)-[relation]->(destination)`
+// request into the store representation.
+func ToRelationPattern(origin, destination, relation *Neo4JObject) store.RelationPattern {
+	return store.RelationPattern{
+		Origin:      origin.ToStore(),
+		Relation:    relation.ToStore(),
+		Destination: destination.ToStore(),
+	}
+}
 
-		i := 1
-		for property := range self.Properties {
-			b.WriteString(property)
-			b.WriteString(": ")
-			b.WriteRune('$')
-			b.WriteString(queryId)
-			b.WriteRune('_')
-			b.WriteString(property)
+// ValidateRelationPattern validates the three objects that describe a
+// `(origin)-[relation]->(destination)` pattern.
+func ValidateRelationPattern(origin, destination, relation *Neo4JObject) error {
+	if err := origin.Validate("OriginNode", identifier.KindLabel); err != nil {
+		return err
+	}
+	if err := destination.Validate("DestinationNode", identifier.KindLabel); err != nil {
+		return err
+	}
+	return relation.Validate("Relation", identifier.KindRelationType)
+}
 
-			if i != propertiesCount {
-				b.WriteRune(',')
-			}
-			i++
-		}
-		b.WriteString("}")
+// FilterRelationPattern is like ValidateRelationPattern for the endpoints
+// that accept filters, see Neo4JObject.Filter.
+func FilterRelationPattern(origin, destination, relation *Neo4JObject) (store.RelationPattern, error) {
+	originFilter, err := origin.Filter("OriginNode", identifier.KindLabel)
+	if err != nil {
+		return store.RelationPattern{}, err
+	}
+	destinationFilter, err := destination.Filter("DestinationNode", identifier.KindLabel)
+	if err != nil {
+		return store.RelationPattern{}, err
+	}
+	relationFilter, err := relation.Filter("Relation", identifier.KindRelationType)
+	if err != nil {
+		return store.RelationPattern{}, err
 	}
-	b.WriteString(limits[1])
+	return store.RelationPattern{Origin: originFilter, Relation: relationFilter, Destination: destinationFilter}, nil
 }
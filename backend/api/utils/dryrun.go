@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DryRunSampleSize is how many of the affected entities a dry run returns.
+const DryRunSampleSize = 10
+
+// DryRun reads the `dryRun` URL query. When it's true, destructive handlers
+// only report what they would change.
+func DryRun(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("dryRun")
+	if value == "" {
+		return false, nil
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &FieldError{Field: "dryRun", Err: fmt.Errorf("`%s` is not a boolean", value)}
+	}
+	return dryRun, nil
+}
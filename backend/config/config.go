@@ -9,14 +9,27 @@ import (
 
 type Config struct {
 	APIPort string
+	// SchemaPath is the JSON schema registry nodes and relationships are
+	// validated against, nothing is validated when it's empty.
+	SchemaPath string
 	DatabaseConfig
 	CorsConfig
 }
 
+// Supported values of DB_DRIVER.
+const (
+	DriverNeo4j  = "neo4j"
+	DriverMemory = "memory"
+)
+
 type DatabaseConfig struct {
+	Driver     string
 	DBUri      string
 	DBUser     string
 	DBPassword string
+
+	// FixturePath is the JSON file used to seed the in-memory graph.
+	FixturePath string
 }
 
 type CorsConfig struct {
@@ -30,14 +43,11 @@ type CorsConfig struct {
 // It exits with a fatal error if any required variable is missing.
 func LoadConfig() Config {
 	return Config{
-		APIPort: mustGetEnv("API_PORT"),
+		APIPort:    mustGetEnv("API_PORT"),
+		SchemaPath: getEnvOrDefault("SCHEMA_FILE", ""),
 
 		// Database
-		DatabaseConfig: DatabaseConfig{
-			DBUri:      mustGetEnv("DB_HOST"),
-			DBUser:     mustGetEnv("DB_USER"),
-			DBPassword: mustGetEnv("DB_USER_PASSWORD"),
-		},
+		DatabaseConfig: loadDatabaseConfig(),
 
 		CorsConfig: CorsConfig{
 			AllowedOrigins:      mustGetEnvAsStringSlice("ALLOWED_ORIGINS"),
@@ -48,6 +58,40 @@ func LoadConfig() Config {
 	}
 }
 
+// loadDatabaseConfig reads DB_DRIVER (`neo4j` by default) and only requires
+// the variables used by the selected driver.
+func loadDatabaseConfig() DatabaseConfig {
+	driver := getEnvOrDefault("DB_DRIVER", DriverNeo4j)
+
+	switch driver {
+	case DriverNeo4j:
+		return DatabaseConfig{
+			Driver:     driver,
+			DBUri:      mustGetEnv("DB_HOST"),
+			DBUser:     mustGetEnv("DB_USER"),
+			DBPassword: mustGetEnv("DB_USER_PASSWORD"),
+		}
+	case DriverMemory:
+		return DatabaseConfig{
+			Driver:      driver,
+			FixturePath: getEnvOrDefault("DB_FIXTURE", ""),
+		}
+	}
+
+	log.Fatalf("Environment variable DB_DRIVER must be `%s` or `%s` but was `%s`", DriverNeo4j, DriverMemory, driver)
+	return DatabaseConfig{}
+}
+
+// getEnvOrDefault retrieves the value of the given environment variable
+// or returns fallback if the variable is not set.
+func getEnvOrDefault(key string, fallback string) string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+	return value
+}
+
 // mustGetEnv retrieves the value of the given environment variable
 // or exits with a fatal error if the variable is not set.
 func mustGetEnv(key string) string {
@@ -0,0 +1,120 @@
+package cypher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// Literal writes a property value as a Cypher literal, for scripts that can't
+// send parameters:
+//
+//	Literal([]any{"a", 1.5}) // ["a", 1.5]
+//
+// Temporal and spatial values are written with their constructor functions,
+// such as `date("2024-01-31")`.
+func Literal(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return stringLiteral(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float32:
+		return floatLiteral(float64(v)), nil
+	case float64:
+		return floatLiteral(v), nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			literal, err := Literal(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, literal)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case []string:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return Literal(items)
+	case dbtype.Date:
+		return "date(" + stringLiteral(v.String()) + ")", nil
+	case dbtype.LocalTime:
+		return "localtime(" + stringLiteral(v.String()) + ")", nil
+	case dbtype.Time:
+		return "time(" + stringLiteral(v.String()) + ")", nil
+	case dbtype.LocalDateTime:
+		return "localdatetime(" + stringLiteral(v.String()) + ")", nil
+	case time.Time:
+		return "datetime(" + stringLiteral(v.Format(time.RFC3339Nano)) + ")", nil
+	case dbtype.Duration:
+		return "duration(" + stringLiteral(v.String()) + ")", nil
+	case dbtype.Point2D:
+		return fmt.Sprintf("point({srid: %d, x: %s, y: %s})", v.SpatialRefId, floatLiteral(v.X), floatLiteral(v.Y)), nil
+	case dbtype.Point3D:
+		return fmt.Sprintf("point({srid: %d, x: %s, y: %s, z: %s})", v.SpatialRefId, floatLiteral(v.X), floatLiteral(v.Y), floatLiteral(v.Z)), nil
+	}
+	return "", fmt.Errorf("values of type %T can't be written as Cypher literals", value)
+}
+
+// MapLiteral writes properties as a Cypher map literal, `{key: value}`, with
+// its keys validated and escaped.
+func MapLiteral(properties map[string]any) (string, error) {
+	items := make([]string, 0, len(properties))
+	for _, key := range sortedKeys(properties) {
+		escaped, err := identifier.PropertyKey(key)
+		if err != nil {
+			return "", err
+		}
+		literal, err := Literal(properties[key])
+		if err != nil {
+			return "", fmt.Errorf("`%s`: %w", key, err)
+		}
+		items = append(items, escaped+": "+literal)
+	}
+	return "{" + strings.Join(items, ", ") + "}", nil
+}
+
+// stringLiteral quotes s, JSON string escapes are valid in Cypher.
+func stringLiteral(s string) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// floatLiteral always writes a decimal point or exponent, otherwise Cypher
+// reads the value back as an integer.
+func floatLiteral(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "0.0 / 0.0"
+	case math.IsInf(f, 1):
+		return "1.0 / 0.0"
+	case math.IsInf(f, -1):
+		return "-1.0 / 0.0"
+	}
+	literal := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(literal, ".eEn") {
+		literal += ".0"
+	}
+	return literal
+}
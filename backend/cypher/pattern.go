@@ -0,0 +1,137 @@
+package cypher
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+)
+
+// Direction of a relationship inside a pattern.
+type Direction int
+
+const (
+	Outgoing Direction = iota // (a)-[r]->(b)
+	Incoming                  // (a)<-[r]-(b)
+	Both                      // (a)-[r]-(b)
+)
+
+type element struct {
+	isRelation bool
+	direction  Direction
+	variable   string
+	category   string
+	properties map[string]any
+}
+
+// Pattern is a chain of nodes joined by relationships, for example
+// `(n1:Provider {id: $n1_id})-[r:PRODUCES]->(n2:Product)`.
+//
+// Property values are always sent as parameters named `<variable>_<key>`.
+type Pattern struct {
+	elements []element
+}
+
+// Node starts a new pattern with a single node.
+// `label` and `properties` are optional.
+func Node(variable string, label string, properties map[string]any) Pattern {
+	return Pattern{
+		elements: []element{{variable: variable, category: label, properties: properties}},
+	}
+}
+
+// Rel describes a relationship used to extend a pattern.
+// `relType` and `properties` are optional.
+type Rel struct {
+	Variable   string
+	Type       string
+	Properties map[string]any
+	Direction  Direction
+}
+
+// Related extends the pattern with `rel` and the first node of `next`.
+func (p Pattern) Related(rel Rel, next Pattern) Pattern {
+	elements := make([]element, 0, len(p.elements)+1+len(next.elements))
+	elements = append(elements, p.elements...)
+	elements = append(elements, element{
+		isRelation: true,
+		direction:  rel.Direction,
+		variable:   rel.Variable,
+		category:   rel.Type,
+		properties: rel.Properties,
+	})
+	elements = append(elements, next.elements...)
+	return Pattern{elements: elements}
+}
+
+// To is a shorthand for an outgoing relationship to `next`.
+func (p Pattern) To(variable string, relType string, properties map[string]any, next Pattern) Pattern {
+	return p.Related(Rel{Variable: variable, Type: relType, Properties: properties}, next)
+}
+
+// write renders the pattern into b and registers its parameters in q.
+func (p Pattern) write(b *strings.Builder, q *Query) {
+	for i, el := range p.elements {
+		if !el.isRelation {
+			b.WriteRune('(')
+			writeElementBody(b, q, el, identifier.KindLabel)
+			b.WriteRune(')')
+			continue
+		}
+
+		if el.direction == Incoming {
+			b.WriteString("<-[")
+		} else {
+			b.WriteString("-[")
+		}
+		writeElementBody(b, q, el, identifier.KindRelationType)
+		if el.direction == Outgoing {
+			b.WriteString("]->")
+		} else {
+			b.WriteString("]-")
+		}
+
+		// A relationship must always be followed by a node.
+		if i == len(p.elements)-1 {
+			b.WriteString("()")
+		}
+	}
+}
+
+func writeElementBody(b *strings.Builder, q *Query, el element, kind identifier.Kind) {
+	b.WriteString(el.variable)
+
+	if el.category != "" {
+		if q.check(identifier.Validate(kind, el.category)) {
+			b.WriteRune(':')
+			b.WriteString(identifier.Escape(el.category))
+		}
+	}
+
+	if len(el.properties) == 0 {
+		return
+	}
+
+	b.WriteString(" {")
+	for i, key := range sortedKeys(el.properties) {
+		if !q.check(identifier.Validate(identifier.KindPropertyKey, key)) {
+			continue
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(identifier.Escape(key))
+		b.WriteString(": ")
+		b.WriteString(q.Param(el.variable+"_"+key, el.properties[key]))
+	}
+	b.WriteRune('}')
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
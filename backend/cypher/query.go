@@ -0,0 +1,217 @@
+// Package cypher builds parameterized Cypher queries.
+//
+// Every label, relationship type and property key is validated and escaped with
+// the identifier package, while every value is sent as a query parameter:
+//
+//	q := cypher.New().
+//		Match(cypher.Node("n", "Product", map[string]any{"id": "P1"})).
+//		Return("n").
+//		Limit(1)
+//	query, params, err := q.Build()
+//	// MATCH (n:`Product` {`id`: $n_id}) RETURN n LIMIT $limit
+package cypher
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+)
+
+// Query accumulates clauses and their parameters.
+// The first error found while adding clauses is returned by Build.
+type Query struct {
+	clauses []string
+	params  map[string]any
+	err     error
+}
+
+// New creates an empty query.
+func New() *Query {
+	return &Query{params: make(map[string]any)}
+}
+
+// Match adds a `MATCH` clause with one or more comma separated patterns.
+func (q *Query) Match(patterns ...Pattern) *Query {
+	return q.patternClause("MATCH", patterns)
+}
+
+// OptionalMatch adds an `OPTIONAL MATCH` clause.
+func (q *Query) OptionalMatch(patterns ...Pattern) *Query {
+	return q.patternClause("OPTIONAL MATCH", patterns)
+}
+
+// Create adds a `CREATE` clause.
+func (q *Query) Create(patterns ...Pattern) *Query {
+	return q.patternClause("CREATE", patterns)
+}
+
+// Merge adds a `MERGE` clause.
+func (q *Query) Merge(pattern Pattern) *Query {
+	return q.patternClause("MERGE", []Pattern{pattern})
+}
+
+// Unwind adds an `UNWIND list AS variable` clause. The list is written as is,
+// values must be registered with Param.
+func (q *Query) Unwind(list string, variable string) *Query {
+	return q.clause("UNWIND " + list + " AS " + variable)
+}
+
+// Where adds a `WHERE` clause joining every condition with `AND`.
+// Conditions are written as is, values must be registered with Param.
+func (q *Query) Where(conditions ...string) *Query {
+	if len(conditions) == 0 {
+		return q
+	}
+	return q.clause("WHERE " + strings.Join(conditions, " AND "))
+}
+
+// Set adds a `SET` clause assigning every property to `variable`.
+// Values are sent as parameters named `<variable>_set_<key>`.
+func (q *Query) Set(variable string, properties map[string]any) *Query {
+	return q.setClause("SET", variable, properties)
+}
+
+// OnCreateSet adds an `ON CREATE SET` clause, to be used after Merge.
+func (q *Query) OnCreateSet(variable string, properties map[string]any) *Query {
+	return q.setClause("ON CREATE SET", variable, properties)
+}
+
+// OnMatchSet adds an `ON MATCH SET` clause, to be used after Merge.
+func (q *Query) OnMatchSet(variable string, properties map[string]any) *Query {
+	return q.setClause("ON MATCH SET", variable, properties)
+}
+
+// Remove adds a `REMOVE` clause removing every key from `variable`.
+func (q *Query) Remove(variable string, keys ...string) *Query {
+	if len(keys) == 0 {
+		return q
+	}
+
+	items := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if q.check(identifier.Validate(identifier.KindPropertyKey, key)) {
+			items = append(items, variable+"."+identifier.Escape(key))
+		}
+	}
+	return q.clause("REMOVE " + strings.Join(items, ", "))
+}
+
+// With adds a `WITH` clause.
+func (q *Query) With(items ...string) *Query {
+	return q.clause("WITH " + strings.Join(items, ", "))
+}
+
+// Delete adds a `DELETE` clause.
+func (q *Query) Delete(variables ...string) *Query {
+	return q.clause("DELETE " + strings.Join(variables, ", "))
+}
+
+// DetachDelete adds a `DETACH DELETE` clause.
+func (q *Query) DetachDelete(variables ...string) *Query {
+	return q.clause("DETACH DELETE " + strings.Join(variables, ", "))
+}
+
+// Return adds a `RETURN` clause.
+func (q *Query) Return(items ...string) *Query {
+	return q.clause("RETURN " + strings.Join(items, ", "))
+}
+
+// OrderBy adds an `ORDER BY` clause, items may include `DESC`.
+func (q *Query) OrderBy(items ...string) *Query {
+	if len(items) == 0 {
+		return q
+	}
+	return q.clause("ORDER BY " + strings.Join(items, ", "))
+}
+
+// Skip adds a `SKIP` clause. Non positive values are ignored.
+func (q *Query) Skip(n int) *Query {
+	if n <= 0 {
+		return q
+	}
+	return q.clause("SKIP " + q.Param("skip", n))
+}
+
+// Limit adds a `LIMIT` clause. Non positive values are ignored.
+func (q *Query) Limit(n int) *Query {
+	if n <= 0 {
+		return q
+	}
+	return q.clause("LIMIT " + q.Param("limit", n))
+}
+
+// Raw appends a clause exactly as given.
+func (q *Query) Raw(clause string) *Query {
+	return q.clause(clause)
+}
+
+// Param registers a parameter and returns its placeholder, for example `$name`.
+// Registering the same name twice with different values is an error.
+func (q *Query) Param(name string, value any) string {
+	if previous, found := q.params[name]; found && !reflect.DeepEqual(previous, value) {
+		q.check(fmt.Errorf("parameter `%s` registered twice with different values", name))
+	}
+	q.params[name] = value
+	return "$" + name
+}
+
+// Property returns `variable.key` with the key escaped.
+func (q *Query) Property(variable string, key string) string {
+	q.check(identifier.Validate(identifier.KindPropertyKey, key))
+	return variable + "." + identifier.Escape(key)
+}
+
+// Build returns the query text and its parameters.
+func (q *Query) Build() (string, map[string]any, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	return strings.Join(q.clauses, " "), q.params, nil
+}
+
+func (q *Query) clause(clause string) *Query {
+	q.clauses = append(q.clauses, clause)
+	return q
+}
+
+func (q *Query) patternClause(keyword string, patterns []Pattern) *Query {
+	b := strings.Builder{}
+	b.WriteString(keyword)
+	b.WriteRune(' ')
+	for i, pattern := range patterns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		pattern.write(&b, q)
+	}
+	return q.clause(b.String())
+}
+
+func (q *Query) setClause(keyword string, variable string, properties map[string]any) *Query {
+	if len(properties) == 0 {
+		return q
+	}
+
+	items := make([]string, 0, len(properties))
+	for _, key := range sortedKeys(properties) {
+		if !q.check(identifier.Validate(identifier.KindPropertyKey, key)) {
+			continue
+		}
+		param := q.Param(variable+"_set_"+key, properties[key])
+		items = append(items, variable+"."+identifier.Escape(key)+" = "+param)
+	}
+	return q.clause(keyword + " " + strings.Join(items, ", "))
+}
+
+// check records err if it's the first one and reports if err was nil.
+func (q *Query) check(err error) bool {
+	if err == nil {
+		return true
+	}
+	if q.err == nil {
+		q.err = err
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package cypher
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+// TestGolden compares the text and parameters of every query with
+// testdata/<name>.golden. Run `go test ./cypher -update` to rewrite them.
+func TestGolden(t *testing.T) {
+	product := Node("n", "Product", map[string]any{"id": "P1", "price": 250})
+
+	tests := []struct {
+		name  string
+		query *Query
+	}{
+		{"match", New().Match(product).Return("n")},
+		{"match_relationship", New().
+			Match(Node("a", "Provider", map[string]any{"id": "PR1"}).
+				To("r", "PRODUCES", map[string]any{"since": "2024-01-01"}, Node("b", "Material", nil))).
+			Return("a", "r", "b")},
+		{"match_incoming", New().
+			Match(Node("a", "", nil).Related(Rel{Variable: "r", Type: "NEEDS", Direction: Incoming}, Node("b", "", nil))).
+			Return("r")},
+		{"match_both_ending_in_relationship", New().
+			Match(Node("a", "", nil).Related(Rel{Variable: "r", Direction: Both}, Pattern{})).
+			Return("count(r) AS count")},
+		{"match_several_patterns", New().
+			Match(Node("a", "Consumer", nil), Node("b", "Retailer", nil)).
+			Return("a", "b")},
+		{"optional_match", New().
+			Match(product).
+			OptionalMatch(Node("n", "", nil).To("r", "NEEDS", nil, Node("m", "Material", nil))).
+			Return("n", "collect(m) AS materials")},
+		{"where", New().
+			Match(Node("n", "Product", nil)).
+			Where("n.price > $min", "n.price < $max").
+			Return("n")},
+		{"where_without_conditions", New().Match(Node("n", "", nil)).Where().Return("n")},
+		{"set", New().
+			Match(product).
+			Set("n", map[string]any{"name": "Silla", "stock": 3}).
+			Return("n")},
+		{"remove", New().Match(product).Remove("n", "stock", "discontinued").Return("n")},
+		{"merge", New().
+			Merge(Node("n", "Product", map[string]any{"id": "P1"})).
+			OnCreateSet("n", map[string]any{"name": "Silla"}).
+			OnMatchSet("n", map[string]any{"price": 300}).
+			Return("n")},
+		{"order_skip_limit", New().
+			Match(Node("n", "Product", nil)).
+			Return("n").
+			OrderBy("n.name", "n.price DESC").
+			Skip(20).
+			Limit(10)},
+		{"non_positive_skip_and_limit", New().Match(Node("n", "", nil)).Return("n").Skip(0).Limit(-1)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text, params, err := test.query.Build()
+			if err != nil {
+				t.Fatalf("Build() failed: %v", err)
+			}
+			encoded, err := json.MarshalIndent(params, "", "\t")
+			if err != nil {
+				t.Fatalf("the parameters couldn't be encoded: %v", err)
+			}
+			got := text + "\n" + string(encoded) + "\n"
+
+			path := filepath.Join("testdata", test.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("the golden file couldn't be read, run with -update to create it: %v", err)
+			}
+			if got != string(want) {
+				t.Errorf("query doesn't match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+			}
+		})
+	}
+}
+
+func TestParamRegisteredTwice(t *testing.T) {
+	q := New()
+	q.Param("id", "P1")
+	if placeholder := q.Param("id", "P1"); placeholder != "$id" {
+		t.Errorf("Param() = %q, want $id", placeholder)
+	}
+	if _, _, err := q.Build(); err != nil {
+		t.Fatalf("registering the same value twice failed: %v", err)
+	}
+
+	q.Param("id", "P2")
+	if _, _, err := q.Build(); err == nil {
+		t.Fatal("registering a different value for the same parameter didn't fail")
+	}
+}
+
+func TestInvalidIdentifiers(t *testing.T) {
+	property := New()
+	property.Raw("RETURN " + property.Property("n", "bad key"))
+
+	tests := map[string]*Query{
+		"label":        New().Match(Node("n", "Bad`Label", nil)),
+		"property key": New().Match(Node("n", "", map[string]any{"bad key": 1})),
+		"set key":      New().Match(Node("n", "", nil)).Set("n", map[string]any{"bad key": 1}),
+		"removed key":  New().Match(Node("n", "", nil)).Remove("n", "bad key"),
+		"property":     property,
+	}
+	for name, q := range tests {
+		if _, _, err := q.Build(); err == nil {
+			t.Errorf("an invalid %s was accepted", name)
+		}
+	}
+}
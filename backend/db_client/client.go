@@ -4,6 +4,9 @@ import (
 	"context"
 
 	"github.com/ElrohirGT/Proyecto1_DB2/config"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/neo4jstore"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
@@ -24,3 +27,22 @@ func NewDriver(config *config.DatabaseConfig) (*neo4j.DriverWithContext, func(co
 	// Return the driver and its close function
 	return &driver, driver.Close, nil
 }
+
+// NewStore creates the GraphStore selected by databaseConfig.Driver along with its close function.
+func NewStore(databaseConfig *config.DatabaseConfig) (store.GraphStore, func(context.Context) error, error) {
+	if databaseConfig.Driver == config.DriverMemory {
+		memory := memstore.New()
+		if databaseConfig.FixturePath != "" {
+			if err := memory.LoadFixture(databaseConfig.FixturePath); err != nil {
+				return nil, nil, err
+			}
+		}
+		return memory, func(context.Context) error { return nil }, nil
+	}
+
+	driver, closeDriver, err := NewDriver(databaseConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return neo4jstore.New(*driver), closeDriver, nil
+}
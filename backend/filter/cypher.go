@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+)
+
+var cypherOperators = map[Op]string{
+	OpEq:         "=",
+	OpNe:         "<>",
+	OpGt:         ">",
+	OpGte:        ">=",
+	OpLt:         "<",
+	OpLte:        "<=",
+	OpIn:         "IN",
+	OpContains:   "CONTAINS",
+	OpStartsWith: "STARTS WITH",
+	OpRegex:      "=~",
+}
+
+// Where renders expr as a condition over the properties of `variable`, to be
+// used in a `WHERE` clause. Values are registered in q as parameters named
+// `<variable>_where_<n>`. A nil expr renders an empty string.
+func Where(q *cypher.Query, variable string, expr Expr) string {
+	if expr == nil {
+		return ""
+	}
+	c := compiler{q: q, variable: variable}
+	return c.compile(expr)
+}
+
+type compiler struct {
+	q        *cypher.Query
+	variable string
+	params   int
+}
+
+func (c *compiler) compile(expr Expr) string {
+	switch e := expr.(type) {
+	case And:
+		return c.join(e, " AND ")
+	case Or:
+		return c.join(e, " OR ")
+	case Condition:
+		property := c.q.Property(c.variable, e.Key)
+		if e.Op == OpExists {
+			if e.Value == true {
+				return property + " IS NOT NULL"
+			}
+			return property + " IS NULL"
+		}
+
+		c.params++
+		param := c.q.Param(fmt.Sprintf("%s_where_%d", c.variable, c.params), e.Value)
+		return fmt.Sprintf("%s %s %s", property, cypherOperators[e.Op], param)
+	}
+	panic(fmt.Sprintf("filter: unknown expression %T", expr))
+}
+
+func (c *compiler) join(exprs []Expr, separator string) string {
+	conditions := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		conditions = append(conditions, c.compile(expr))
+	}
+	return "(" + strings.Join(conditions, separator) + ")"
+}
@@ -0,0 +1,246 @@
+// Package filter parses the property filters clients send to match nodes and
+// relationships.
+//
+// A filter is a JSON object. Plain values must be equal, while objects hold
+// operators and `$and`/`$or` combine nested filters:
+//
+//	{
+//		"category": "Electronics",
+//		"price": {"$gte": 10, "$lt": 100},
+//		"$or": [{"name": {"$startsWith": "Smart"}}, {"brand": {"$in": ["Vertex", "Nova"]}}]
+//	}
+//
+// Neo4j can't store maps as property values, so an object is always read as operators.
+//
+// `$regex` must match the whole string and is run by Neo4j with Java's regular
+// expressions, so only the syntax both Java and Go's RE2 read the same way is
+// accepted. See checkRegex for the details.
+package filter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEq         Op = "$eq"
+	OpNe         Op = "$ne"
+	OpGt         Op = "$gt"
+	OpGte        Op = "$gte"
+	OpLt         Op = "$lt"
+	OpLte        Op = "$lte"
+	OpIn         Op = "$in"
+	OpContains   Op = "$contains"
+	OpStartsWith Op = "$startsWith"
+	OpExists     Op = "$exists"
+	OpRegex      Op = "$regex"
+)
+
+const (
+	keyAnd = "$and"
+	keyOr  = "$or"
+)
+
+// Expr is a boolean expression over the properties of a node or relationship.
+// It's one of Condition, And or Or.
+type Expr interface {
+	expr()
+}
+
+// Condition compares the property Key against Value.
+type Condition struct {
+	Key   string
+	Op    Op
+	Value any
+}
+
+// And is true when every expression is true.
+type And []Expr
+
+// Or is true when any expression is true.
+type Or []Expr
+
+func (Condition) expr() {}
+func (And) expr()       {}
+func (Or) expr()        {}
+
+// Error is returned when a filter can't be parsed.
+type Error struct {
+	// Path to the offending part of the filter, for example `price.$gt`.
+	Path   string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("`%s`: %s", e.Path, e.Reason)
+}
+
+// Parse splits a filter into its top level equalities, which can be written
+// inside a Cypher pattern as `{key: value}`, and an expression with
+// everything else. The expression is nil when the filter only has equalities.
+func Parse(filter map[string]any) (equal map[string]any, where Expr, err error) {
+	equal = make(map[string]any)
+	var conditions And
+
+	for _, key := range sortedKeys(filter) {
+		value := filter[key]
+		if _, isOperator := value.(map[string]any); isOperator || key == keyAnd || key == keyOr {
+			expr, err := parseEntry("", key, value)
+			if err != nil {
+				return nil, nil, err
+			}
+			conditions = append(conditions, expr)
+			continue
+		}
+
+		if err := identifier.Validate(identifier.KindPropertyKey, key); err != nil {
+			return nil, nil, &Error{Path: key, Reason: err.Error()}
+		}
+		equal[key] = value
+	}
+
+	return equal, simplify(conditions), nil
+}
+
+// parseObject parses a nested filter, where equalities are conditions too.
+func parseObject(path string, filter map[string]any) (Expr, error) {
+	if len(filter) == 0 {
+		return nil, &Error{Path: path, Reason: "the filter can't be empty"}
+	}
+
+	var conditions And
+	for _, key := range sortedKeys(filter) {
+		expr, err := parseEntry(path, key, filter[key])
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, expr)
+	}
+	return simplify(conditions), nil
+}
+
+func parseEntry(path string, key string, value any) (Expr, error) {
+	path = join(path, key)
+
+	if key == keyAnd || key == keyOr {
+		items, isList := value.([]any)
+		if !isList || len(items) == 0 {
+			return nil, &Error{Path: path, Reason: "it must be a non empty list of filters"}
+		}
+
+		exprs := make([]Expr, 0, len(items))
+		for i, item := range items {
+			object, isObject := item.(map[string]any)
+			if !isObject {
+				return nil, &Error{Path: fmt.Sprintf("%s[%d]", path, i), Reason: "it must be a filter object"}
+			}
+			expr, err := parseObject(fmt.Sprintf("%s[%d]", path, i), object)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+
+		if key == keyAnd {
+			return And(exprs), nil
+		}
+		return Or(exprs), nil
+	}
+
+	if err := identifier.Validate(identifier.KindPropertyKey, key); err != nil {
+		return nil, &Error{Path: path, Reason: err.Error()}
+	}
+
+	operators, isOperator := value.(map[string]any)
+	if !isOperator {
+		return Condition{Key: key, Op: OpEq, Value: value}, nil
+	}
+	if len(operators) == 0 {
+		return nil, &Error{Path: path, Reason: "it must have at least one operator"}
+	}
+
+	var conditions And
+	for _, op := range sortedKeys(operators) {
+		condition := Condition{Key: key, Op: Op(op), Value: operators[op]}
+		if err := condition.validate(); err != nil {
+			return nil, &Error{Path: join(path, op), Reason: err.Error()}
+		}
+		conditions = append(conditions, condition)
+	}
+	return simplify(conditions), nil
+}
+
+func (c Condition) validate() error {
+	switch c.Op {
+	case OpEq, OpNe:
+		if _, isMap := c.Value.(map[string]any); isMap {
+			return fmt.Errorf("it can't be an object")
+		}
+	case OpGt, OpGte, OpLt, OpLte:
+		switch c.Value.(type) {
+		case float64, int, int64, string:
+		default:
+			return fmt.Errorf("it must be a number or a string")
+		}
+	case OpIn:
+		if _, isList := c.Value.([]any); !isList {
+			return fmt.Errorf("it must be a list")
+		}
+	case OpContains, OpStartsWith:
+		if _, isString := c.Value.(string); !isString {
+			return fmt.Errorf("it must be a string")
+		}
+	case OpExists:
+		if _, isBool := c.Value.(bool); !isBool {
+			return fmt.Errorf("it must be a boolean")
+		}
+	case OpRegex:
+		pattern, isString := c.Value.(string)
+		if !isString {
+			return fmt.Errorf("it must be a string")
+		}
+		if err := checkRegex(pattern); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown operator")
+	}
+	return nil
+}
+
+// simplify avoids wrapping a single expression in an And.
+func simplify(conditions And) Expr {
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return conditions[0]
+	}
+	return conditions
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys makes parsing deterministic, so the same filter always
+// produces the same query.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
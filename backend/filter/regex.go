@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// checkRegex validates a `$regex` pattern. Go checks it with RE2 but Neo4j
+// runs it as a java.util.regex.Pattern, so besides compiling it this rejects
+// the constructs both engines accept with a different meaning, or that only
+// RE2 accepts:
+//
+//   - `[` inside a character class, Java reads it as a nested class (this
+//     also covers POSIX classes like `[[:alpha:]]`).
+//   - `&&` inside a character class, Java reads it as an intersection.
+//   - `\v`, a vertical tab in RE2 and any vertical whitespace in Java.
+//   - `\C` and octal escapes like `\12`, which don't exist or mean a
+//     backreference in Java.
+//   - `(?P<name>...)` groups, write `(?<name>...)` instead.
+//   - The `U` flag, ungreedy in RE2 and Unicode classes in Java.
+//
+// What's left still differs in small ways that are documented instead: `(?i)`
+// only folds ASCII letters in Java, and Java's `.` and `\s` also treat `\r`
+// and a few other line terminators as line ends or whitespace.
+func checkRegex(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			switch escaped := pattern[i]; {
+			case escaped == 'Q':
+				// Everything up to `\E` is literal in both engines.
+				end := strings.Index(pattern[i+1:], `\E`)
+				if end < 0 {
+					return nil
+				}
+				i += end + 2
+			case escaped == 'v' || escaped == 'C':
+				return fmt.Errorf("`\\%c` isn't supported by Neo4j regular expressions", escaped)
+			case escaped >= '0' && escaped <= '7':
+				return fmt.Errorf("octal escapes aren't supported by Neo4j regular expressions, use `\\x{...}`")
+			}
+		case inClass:
+			switch {
+			case c == ']':
+				inClass = false
+			case c == '[':
+				return fmt.Errorf("`[` must be escaped inside a character class")
+			case c == '&' && strings.HasPrefix(pattern[i+1:], "&"):
+				return fmt.Errorf("`&&` must be escaped inside a character class")
+			}
+		case c == '[':
+			inClass = true
+			// A `]` right after the opening bracket is a literal.
+			if strings.HasPrefix(pattern[i+1:], "^") {
+				i++
+			}
+			if strings.HasPrefix(pattern[i+1:], "]") {
+				i++
+			}
+		case c == '(' && strings.HasPrefix(pattern[i+1:], "?"):
+			group := pattern[i+2:]
+			if strings.HasPrefix(group, "P<") {
+				return fmt.Errorf("`(?P<name>...)` isn't supported by Neo4j regular expressions, use `(?<name>...)`")
+			}
+			flags := group[:len(group)-len(strings.TrimLeft(group, "imsU-"))]
+			if strings.Contains(flags, "U") {
+				return fmt.Errorf("the `U` flag isn't supported by Neo4j regular expressions")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package filter
+
+import "testing"
+
+func TestCheckRegex(t *testing.T) {
+	tests := []struct {
+		pattern string
+		valid   bool
+	}{
+		{`Smart.*`, true},
+		{`(?i)^vertex\s+\d{2,}$`, true},
+		{`[a-z&]+`, true},
+		{`[]a]`, true},
+		{`[^]a]`, true},
+		{`[\[\]]`, true},
+		{`\Q[[&&\E`, true},
+		{`(?<model>X\d)`, true},
+		{`(?<Unit>x)`, true},
+		{`(?s:.)`, true},
+		{`(`, false},
+		{`[[:alpha:]]`, false},
+		{`[a-z&&[^aeiou]]`, false},
+		{`\v`, false},
+		{`\C`, false},
+		{`\12`, false},
+		{`(?P<model>X)`, false},
+		{`(?U)a+`, false},
+		{`(?iU:a+)`, false},
+	}
+
+	for _, test := range tests {
+		err := checkRegex(test.pattern)
+		if test.valid && err != nil {
+			t.Errorf("checkRegex(%q) = %v, want nil", test.pattern, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("checkRegex(%q) = nil, want an error", test.pattern)
+		}
+	}
+}
@@ -2,13 +2,16 @@ module github.com/ElrohirGT/Proyecto1_DB2
 
 go 1.23.6
 
+require (
+	github.com/neo4j/neo4j-go-driver/v5 v5.27.0
+	github.com/rs/zerolog v1.33.0
+)
+
 require (
 	github.com/go-chi/chi/v5 v5.2.1 // indirect
 	github.com/go-chi/cors v1.2.1 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
-	github.com/neo4j/neo4j-go-driver/v5 v5.27.0 // indirect
-	github.com/rs/zerolog v1.33.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 )
@@ -0,0 +1,111 @@
+// Package identifier validates and escapes the user supplied names that end up
+// spliced into Cypher text: node labels, relationship types, property keys and
+// the names of constraints and indexes.
+//
+// Cypher can't receive these as query parameters, so every handler must run
+// them through this package before writing them into a query.
+package identifier
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind describes what an identifier is used for inside a query.
+type Kind string
+
+const (
+	KindLabel        Kind = "label"
+	KindRelationType Kind = "relationship type"
+	KindPropertyKey  Kind = "property key"
+	KindSchemaName   Kind = "constraint or index name"
+)
+
+// MaxLength is the maximum amount of characters an identifier may have.
+const MaxLength = 128
+
+// Error is returned when an identifier is rejected.
+type Error struct {
+	Kind   Kind
+	Value  string
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Kind, e.Value, e.Reason)
+}
+
+// Validate checks that value is a safe identifier of the given kind.
+//
+// Identifiers must start with a letter or an underscore and may only contain
+// letters, digits and underscores.
+func Validate(kind Kind, value string) error {
+	if value == "" {
+		return &Error{Kind: kind, Value: value, Reason: "it can't be empty"}
+	}
+
+	if utf8.RuneCountInString(value) > MaxLength {
+		return &Error{Kind: kind, Value: value, Reason: fmt.Sprintf("it can't be longer than %d characters", MaxLength)}
+	}
+
+	for i, r := range value {
+		if i == 0 && !(unicode.IsLetter(r) || r == '_') {
+			return &Error{Kind: kind, Value: value, Reason: "it must start with a letter or an underscore"}
+		}
+
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			return &Error{Kind: kind, Value: value, Reason: fmt.Sprintf("character %q is not allowed", r)}
+		}
+	}
+
+	return nil
+}
+
+// Escape wraps name in backticks so Cypher always reads it as a single identifier.
+func Escape(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Label validates and escapes a node label.
+func Label(value string) (string, error) {
+	return validateAndEscape(KindLabel, value)
+}
+
+// RelationType validates and escapes a relationship type.
+func RelationType(value string) (string, error) {
+	return validateAndEscape(KindRelationType, value)
+}
+
+// PropertyKey validates and escapes a property key.
+func PropertyKey(value string) (string, error) {
+	return validateAndEscape(KindPropertyKey, value)
+}
+
+// PropertyKeys validates every key of a property map.
+func PropertyKeys(properties map[string]any) error {
+	for key := range properties {
+		if err := Validate(KindPropertyKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PropertyKeyList validates every property key in the slice.
+func PropertyKeyList(keys []string) error {
+	for _, key := range keys {
+		if err := Validate(KindPropertyKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateAndEscape(kind Kind, value string) (string, error) {
+	if err := Validate(kind, value); err != nil {
+		return "", err
+	}
+	return Escape(value), nil
+}
@@ -0,0 +1,128 @@
+package identifier
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"letters", "Product", true},
+		{"leading underscore", "_internal", true},
+		{"digits and underscores", "price_2024", true},
+		{"unicode letters", "Categoría", true},
+		{"non latin letters", "産品", true},
+		{"reserved word", "MATCH", true},
+		{"reserved word lowercase", "return", true},
+		{"longest allowed", strings.Repeat("a", MaxLength), true},
+		{"longest allowed in runes", strings.Repeat("á", MaxLength), true},
+
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", MaxLength+1), false},
+		{"leading digit", "1st", false},
+		{"backtick", "a`b", false},
+		{"closing backtick injection", "x` {}) DETACH DELETE n //", false},
+		{"space", "first name", false},
+		{"leading space", " name", false},
+		{"trailing newline", "name\n", false},
+		{"tab", "a\tb", false},
+		{"dash", "sub-material", false},
+		{"dot", "n.name", false},
+		{"colon", "Product:Material", false},
+		{"pipe", "NEEDS|PRODUCES", false},
+		{"parameter", "$param", false},
+		{"braces", "{}", false},
+		{"quote", `a"b`, false},
+		{"unicode punctuation", "a·b", false},
+		{"null byte", "a\x00b", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(KindLabel, test.value)
+			if test.valid && err != nil {
+				t.Errorf("Validate(%q) = %v, want nil", test.value, err)
+			}
+			if !test.valid {
+				var identifierErr *Error
+				if !errors.As(err, &identifierErr) {
+					t.Fatalf("Validate(%q) = %v, want an *Error", test.value, err)
+				}
+				if identifierErr.Kind != KindLabel || identifierErr.Value != test.value {
+					t.Errorf("got error for %s %q, want %s %q", identifierErr.Kind, identifierErr.Value, KindLabel, test.value)
+				}
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Product", "`Product`"},
+		{"MATCH", "`MATCH`"},
+		{"first name", "`first name`"},
+		{"", "``"},
+		{"a`b", "`a``b`"},
+		{"`", "````"},
+		{"x` {}) DETACH DELETE n //", "`x`` {}) DETACH DELETE n //`"},
+		{"Categoría", "`Categoría`"},
+	}
+
+	for _, test := range tests {
+		if got := Escape(test.value); got != test.want {
+			t.Errorf("Escape(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestValidateAndEscape(t *testing.T) {
+	tests := []struct {
+		escape func(string) (string, error)
+		kind   Kind
+	}{
+		{Label, KindLabel},
+		{RelationType, KindRelationType},
+		{PropertyKey, KindPropertyKey},
+	}
+
+	for _, test := range tests {
+		escaped, err := test.escape("DELETE")
+		if err != nil || escaped != "`DELETE`" {
+			t.Errorf("%s: got %s, %v, want `DELETE`", test.kind, escaped, err)
+		}
+
+		for _, value := range []string{"", "a`b", "a b"} {
+			escaped, err := test.escape(value)
+			var identifierErr *Error
+			if !errors.As(err, &identifierErr) || identifierErr.Kind != test.kind {
+				t.Errorf("%s %q: got error %v, want an *Error of kind %s", test.kind, value, err, test.kind)
+			}
+			if escaped != "" {
+				t.Errorf("%s %q: got %s, want nothing written when it's invalid", test.kind, value, escaped)
+			}
+		}
+	}
+}
+
+func TestPropertyKeys(t *testing.T) {
+	if err := PropertyKeys(map[string]any{"name": 1, "_id": 2}); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if err := PropertyKeys(map[string]any{"name": 1, "bad key": 2}); err == nil {
+		t.Error("got nil, want an error for `bad key`")
+	}
+	if err := PropertyKeyList([]string{"name", "price"}); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if err := PropertyKeyList([]string{"name", "n.price"}); err == nil {
+		t.Error("got nil, want an error for `n.price`")
+	}
+}
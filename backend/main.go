@@ -2,14 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/ElrohirGT/Proyecto1_DB2/api"
+	"github.com/ElrohirGT/Proyecto1_DB2/api/apierror"
 	mw "github.com/ElrohirGT/Proyecto1_DB2/api/middlewares"
 	"github.com/ElrohirGT/Proyecto1_DB2/config"
 	"github.com/ElrohirGT/Proyecto1_DB2/db_client"
+	"github.com/ElrohirGT/Proyecto1_DB2/schema"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
 	"github.com/ElrohirGT/Proyecto1_DB2/utils"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
 )
@@ -25,10 +30,10 @@ func main() {
 	utils.ConfigureLogger()
 
 	// Database Client
-	dbClient, closeDB, err := db_client.NewDriver(&config.DatabaseConfig)
+	db, closeDB, err := db_client.NewStore(&config.DatabaseConfig)
 
 	if err != nil {
-		log.Panic().Err(err).Msg("Failed to create Neo4j driver: %v")
+		log.Panic().Err(err).Str("driver", config.DatabaseConfig.Driver).Msg("Failed to create the graph store")
 	}
 	defer closeDB(context.Background())
 
@@ -38,15 +43,34 @@ func main() {
 			Err(err)
 	}
 
+	// Schema registry
+	var registry *schema.Registry
+	if config.SchemaPath != "" {
+		registry, err = schema.Load(config.SchemaPath)
+		if err != nil {
+			log.Fatal().Err(err).Str("path", config.SchemaPath).Msg("Failed to load the schema registry")
+		}
+		log.Info().Str("path", config.SchemaPath).Msg("✅ Schema registry loaded")
+	}
+	bootstrapSchema(context.Background(), db, registry)
+
 	// App and Services Configuration
-	app := api.NewApi(dbClient)
+	app := api.NewApi(db, registry)
 
 	// Routes
 	r := chi.NewRouter()
 
+	r.Use(middleware.RequestID)
 	r.Use(mw.Logging)
 	r.Use(mw.CreateCors(config.CorsConfig))
 
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		apierror.NotFound(w, r, "Route not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, http.StatusMethodNotAllowed, apierror.CodeMethodNotAllowed, "Method not allowed", nil)
+	})
+
 	r.Get("/health", app.CheckHealthHandler)
 
 	r.Group(func(r chi.Router) {
@@ -56,6 +80,10 @@ func main() {
 		r.Get("/node", app.ReadNodeHandler)
 		r.Put("/node", app.UpdateNodeHandler)
 		r.Delete("/node", app.DeleteNodeHandler)
+		r.Get("/nodes", app.ListNodesHandler)
+		r.Get("/node/{elementId}", app.ReadNodeByIdHandler)
+		r.Put("/node/{elementId}", app.UpdateNodeByIdHandler)
+		r.Delete("/node/{elementId}", app.DeleteNodeByIdHandler)
 		r.Delete("/nodes", app.DeleteManyNodesHandler)
 
 		// Multiple Nodes
@@ -70,10 +98,39 @@ func main() {
 		r.Put("/relation", app.UpdateRelationHandler)
 		r.Delete("/relation", app.DeleteRelationHandler)
 		r.Delete("/relations", app.DeleteManyRelationsHandler)
+		r.Get("/relation/{elementId}", app.ReadRelationByIdHandler)
+		r.Put("/relation/{elementId}", app.UpdateRelationByIdHandler)
+		r.Delete("/relation/{elementId}", app.DeleteRelationByIdHandler)
+
+		// Batch
+		r.Post("/batch", app.BatchHandler)
+
+		// Import
+		r.Post("/import/nodes", app.ImportNodesHandler)
+		r.Post("/import/relations", app.ImportRelationsHandler)
+
+		// Export
+		r.Get("/export", app.ExportHandler)
+
+		// Schema
+		r.Get("/schema", app.SchemaHandler)
+
+		// Admin
+		r.Get("/admin/constraints", app.ListConstraintsHandler)
+		r.Post("/admin/constraints", app.CreateConstraintHandler)
+		r.Delete("/admin/constraints/{name}", app.DropConstraintHandler)
+		r.Get("/admin/indexes", app.ListIndexesHandler)
+		r.Post("/admin/indexes", app.CreateIndexHandler)
+		r.Delete("/admin/indexes/{name}", app.DropIndexHandler)
 
 		// Functional requirements
 		r.Get("/history", app.GetProductHistoryHandler)
+		r.Get("/history/bom", app.GetBillOfMaterialsHandler)
+		r.Get("/impact", app.GetImpactHandler)
+		r.Get("/paths", app.GetPathsHandler)
+		r.Get("/risks", app.GetSupplyRisksHandler)
 		r.Get("/statistics", app.GetStatisticsHandler)
+		r.Get("/statistics/sales", app.GetSalesHandler)
 
 	})
 
@@ -81,3 +138,34 @@ func main() {
 	log.Printf("Starting server on port %s", config.APIPort)
 	log.Fatal().Err(http.ListenAndServe(":"+config.APIPort, r))
 }
+
+// bootstrapSchema creates the constraints and indexes declared by the schema
+// registry, the ones that already exist are left as they are. A failure is
+// logged but doesn't stop the server, since existing duplicates would block a
+// uniqueness constraint forever.
+func bootstrapSchema(ctx context.Context, db store.GraphStore, registry *schema.Registry) {
+	create := func(kind string, spec store.IndexSpec, createFn func(context.Context, store.IndexSpec) (store.SchemaChange, error)) bool {
+		change, err := createFn(ctx, spec)
+		if errors.Is(err, store.ErrUnsupported) {
+			log.Warn().Msg("The graph store has no constraints or indexes, skipping the schema bootstrap")
+			return false
+		}
+		if err != nil {
+			log.Error().Err(err).Str("label", spec.Label).Strs("properties", spec.Properties).Msgf("❌ Could not create the %s", kind)
+			return true
+		}
+		log.Info().Str("name", change.Name).Bool("created", change.Created).Msgf("✅ %s ready", kind)
+		return true
+	}
+
+	for _, spec := range registry.Constraints() {
+		if !create("constraint", spec, db.CreateUniqueConstraint) {
+			return
+		}
+	}
+	for _, spec := range registry.Indexes() {
+		if !create("index", spec, db.CreateIndex) {
+			return
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Package schema declares the properties every node label and relationship
+// type must have, and the labels a relationship may connect.
+//
+// The registry is a JSON document:
+//
+//	{
+//		"strict": false,
+//		"labels": {
+//			"Product": {"properties": {
+//				"id": {"type": "string", "required": true},
+//				"price": {"type": "number", "min": 0}
+//			}}
+//		},
+//		"relations": {
+//			"RATES": {
+//				"endpoints": [{"from": "Consumer", "to": "Product"}],
+//				"properties": {"rating": {"type": "integer", "required": true, "min": 1, "max": 5}}
+//			}
+//		}
+//	}
+//
+// Node properties may also be `"unique": true` or have an `"index"` of type
+// `range` or `text`, main.go creates those constraints and indexes at startup.
+//
+// Properties that aren't declared are allowed. Labels and relationship types
+// that aren't declared are allowed too, unless `strict` is true.
+//
+// A nil *Registry accepts everything, so the API works without a schema.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// Type is the type of a property value.
+type Type string
+
+const (
+	TypeString  Type = "string"
+	TypeInteger Type = "integer"
+	TypeNumber  Type = "number"
+	TypeBoolean Type = "boolean"
+	// TypeDate is anything store.DateOf reads: a Neo4j date or date time, or a
+	// string starting with `YYYY-MM-DD`.
+	TypeDate Type = "date"
+	TypeList Type = "list"
+)
+
+type Property struct {
+	Type     Type `json:"type"`
+	Required bool `json:"required,omitempty"`
+	// Min and Max bound number and integer values.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Unique and Index are only allowed on node properties.
+	Unique bool   `json:"unique,omitempty"`
+	Index  string `json:"index,omitempty"`
+}
+
+type Label struct {
+	Properties map[string]Property `json:"properties"`
+}
+
+// Endpoint is a pair of labels a relationship may go from and to.
+type Endpoint struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Relation struct {
+	Properties map[string]Property `json:"properties"`
+	// Endpoints lists the labels the relationship may connect, any labels
+	// are allowed when it's empty.
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+}
+
+type Registry struct {
+	// Strict rejects labels and relationship types that aren't declared.
+	Strict    bool                `json:"strict"`
+	Labels    map[string]Label    `json:"labels"`
+	Relations map[string]Relation `json:"relations"`
+}
+
+// Load reads the registry stored at path.
+func Load(path string) (*Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Parse(file)
+}
+
+// Parse decodes a registry from r and checks it's valid.
+func Parse(r io.Reader) (*Registry, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var registry Registry
+	if err := decoder.Decode(&registry); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	for name, label := range registry.Labels {
+		if err := identifier.Validate(identifier.KindLabel, name); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		if err := validateProperties(label.Properties, true); err != nil {
+			return nil, fmt.Errorf("invalid schema: label `%s`: %w", name, err)
+		}
+	}
+	for name, relation := range registry.Relations {
+		if err := identifier.Validate(identifier.KindRelationType, name); err != nil {
+			return nil, fmt.Errorf("invalid schema: %w", err)
+		}
+		if err := validateProperties(relation.Properties, false); err != nil {
+			return nil, fmt.Errorf("invalid schema: relation `%s`: %w", name, err)
+		}
+		for _, endpoint := range relation.Endpoints {
+			for _, label := range []string{endpoint.From, endpoint.To} {
+				if err := identifier.Validate(identifier.KindLabel, label); err != nil {
+					return nil, fmt.Errorf("invalid schema: relation `%s`: %w", name, err)
+				}
+			}
+		}
+	}
+	return &registry, nil
+}
+
+func validateProperties(properties map[string]Property, isNode bool) error {
+	for key, property := range properties {
+		if err := identifier.Validate(identifier.KindPropertyKey, key); err != nil {
+			return err
+		}
+		switch property.Type {
+		case TypeString, TypeInteger, TypeNumber, TypeBoolean, TypeDate, TypeList:
+		default:
+			return fmt.Errorf("property `%s` has an unknown type `%s`", key, property.Type)
+		}
+		if property.Min != nil && property.Max != nil && *property.Min > *property.Max {
+			return fmt.Errorf("property `%s` has a `min` greater than its `max`", key)
+		}
+		if !isNode && (property.Unique || property.Index != "") {
+			return fmt.Errorf("property `%s` can't be unique or indexed, only node properties can", key)
+		}
+		switch store.IndexType(strings.ToUpper(property.Index)) {
+		case "", store.IndexText:
+		case store.IndexRange:
+			if property.Unique {
+				return fmt.Errorf("property `%s` is unique, its constraint already indexes it", key)
+			}
+		default:
+			return fmt.Errorf("property `%s` has an unknown index `%s`", key, property.Index)
+		}
+	}
+	return nil
+}
+
+// Constraints returns a uniqueness constraint for every unique node property.
+func (r *Registry) Constraints() []store.IndexSpec {
+	return r.indexSpecs(func(property Property) (store.IndexType, bool) {
+		return "", property.Unique
+	})
+}
+
+// Indexes returns an index for every indexed node property.
+func (r *Registry) Indexes() []store.IndexSpec {
+	return r.indexSpecs(func(property Property) (store.IndexType, bool) {
+		return store.IndexType(strings.ToUpper(property.Index)), property.Index != ""
+	})
+}
+
+// indexSpecs returns a spec for every node property selected by include,
+// sorted by label and property.
+func (r *Registry) indexSpecs(include func(Property) (store.IndexType, bool)) []store.IndexSpec {
+	if r == nil {
+		return nil
+	}
+
+	var specs []store.IndexSpec
+	for name, label := range r.Labels {
+		for key, property := range label.Properties {
+			if indexType, included := include(property); included {
+				specs = append(specs, store.IndexSpec{Type: indexType, Label: name, Properties: []string{key}})
+			}
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].Label != specs[j].Label {
+			return specs[i].Label < specs[j].Label
+		}
+		return specs[i].Properties[0] < specs[j].Properties[0]
+	})
+	return specs
+}
+
+// Violation is a single way in which a node or relationship breaks the schema.
+type Violation struct {
+	Property string `json:"property,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// Error lists every violation found in a node or relationship.
+type Error struct {
+	// Subject is the label or relationship type that was checked.
+	Subject    string      `json:"subject"`
+	Violations []Violation `json:"violations"`
+}
+
+func (e *Error) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		if violation.Property == "" {
+			reasons = append(reasons, violation.Reason)
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("`%s` %s", violation.Property, violation.Reason))
+	}
+	return fmt.Sprintf("`%s` doesn't match the schema: %s", e.Subject, strings.Join(reasons, "; "))
+}
+
+// CheckNode checks a new node with the given labels and properties.
+func (r *Registry) CheckNode(labels []string, properties map[string]any) error {
+	return r.checkNode(labels, properties, true)
+}
+
+// CheckNodeUpdate checks the properties set on existing nodes with the given
+// labels, a nil value removes a property.
+func (r *Registry) CheckNodeUpdate(labels []string, properties map[string]any) error {
+	return r.checkNode(labels, properties, false)
+}
+
+// CheckNodeRemoval checks removing keys from existing nodes with the given labels.
+func (r *Registry) CheckNodeRemoval(labels []string, keys []string) error {
+	return r.CheckNodeUpdate(labels, removed(keys))
+}
+
+// CheckRelation checks a new relationship between nodes with the given labels.
+func (r *Registry) CheckRelation(relType string, from []string, to []string, properties map[string]any) error {
+	if r == nil {
+		return nil
+	}
+
+	relation, declared := r.Relations[relType]
+	if !declared {
+		return r.undeclared(relType, "relationship type")
+	}
+
+	violations := checkProperties(relation.Properties, properties, true)
+	if len(relation.Endpoints) > 0 && !slices.ContainsFunc(relation.Endpoints, func(endpoint Endpoint) bool {
+		return slices.Contains(from, endpoint.From) && slices.Contains(to, endpoint.To)
+	}) {
+		allowed := make([]string, 0, len(relation.Endpoints))
+		for _, endpoint := range relation.Endpoints {
+			allowed = append(allowed, fmt.Sprintf("%s to %s", endpoint.From, endpoint.To))
+		}
+		violations = append(violations, Violation{Reason: fmt.Sprintf(
+			"it can't go from %s to %s, the allowed endpoints are %s",
+			labelList(from), labelList(to), strings.Join(allowed, ", "),
+		)})
+	}
+	return asError(relType, violations)
+}
+
+// CheckRelationUpdate checks the properties set on existing relationships of
+// the given type, a nil value removes a property.
+func (r *Registry) CheckRelationUpdate(relType string, properties map[string]any) error {
+	if r == nil {
+		return nil
+	}
+
+	relation, declared := r.Relations[relType]
+	if !declared {
+		return r.undeclared(relType, "relationship type")
+	}
+	return asError(relType, checkProperties(relation.Properties, properties, false))
+}
+
+// CheckRelationRemoval checks removing keys from existing relationships of the given type.
+func (r *Registry) CheckRelationRemoval(relType string, keys []string) error {
+	return r.CheckRelationUpdate(relType, removed(keys))
+}
+
+func (r *Registry) checkNode(labels []string, properties map[string]any, creating bool) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, name := range labels {
+		label, declared := r.Labels[name]
+		if !declared {
+			if err := r.undeclared(name, "label"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := asError(name, checkProperties(label.Properties, properties, creating)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registry) undeclared(subject string, kind string) error {
+	if !r.Strict {
+		return nil
+	}
+	return &Error{Subject: subject, Violations: []Violation{{Reason: fmt.Sprintf("the %s isn't declared", kind)}}}
+}
+
+// checkProperties compares values against the declared properties. Missing
+// required properties are only reported when creating.
+func checkProperties(declared map[string]Property, values map[string]any, creating bool) []Violation {
+	var violations []Violation
+	for key, property := range declared {
+		value, found := values[key]
+		switch {
+		case value != nil:
+			if reason := property.check(value); reason != "" {
+				violations = append(violations, Violation{Property: key, Reason: reason})
+			}
+		case !property.Required:
+		case creating:
+			violations = append(violations, Violation{Property: key, Reason: "is required"})
+		case found:
+			violations = append(violations, Violation{Property: key, Reason: "is required and can't be removed"})
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Property < violations[j].Property })
+	return violations
+}
+
+// check returns why value doesn't match the property, or an empty string.
+func (p Property) check(value any) string {
+	switch p.Type {
+	case TypeString:
+		if _, isString := value.(string); !isString {
+			return "must be a string"
+		}
+		return ""
+	case TypeBoolean:
+		if _, isBool := value.(bool); !isBool {
+			return "must be a boolean"
+		}
+		return ""
+	case TypeDate:
+		if _, isDate := store.DateOf(value); isDate {
+			return ""
+		}
+		return "must be a date formatted as YYYY-MM-DD, optionally followed by a time"
+	case TypeList:
+		switch value.(type) {
+		case []any, []string:
+			return ""
+		}
+		return "must be a list"
+	}
+
+	number, isNumber := toFloat(value)
+	if !isNumber {
+		return fmt.Sprintf("must be a %s", p.Type)
+	}
+	if p.Type == TypeInteger && number != math.Trunc(number) {
+		return "must be an integer"
+	}
+	if p.Min != nil && number < *p.Min {
+		return fmt.Sprintf("must be at least %v", *p.Min)
+	}
+	if p.Max != nil && number > *p.Max {
+		return fmt.Sprintf("must be at most %v", *p.Max)
+	}
+	return ""
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func asError(subject string, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Subject: subject, Violations: violations}
+}
+
+func removed(keys []string) map[string]any {
+	properties := make(map[string]any, len(keys))
+	for _, key := range keys {
+		properties[key] = nil
+	}
+	return properties
+}
+
+func labelList(labels []string) string {
+	if len(labels) == 0 {
+		return "a node without labels"
+	}
+	return strings.Join(labels, ":")
+}
@@ -0,0 +1,42 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestDateProperty(t *testing.T) {
+	registry, err := Load("supply_chain.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer, retailer := []string{"Consumer"}, []string{"Retailer"}
+
+	tests := []struct {
+		date  any
+		valid bool
+	}{
+		{"2024-05-01", true},
+		{"2024-05-01T10:00", true},
+		{"2024-05-01T10:00:00Z", true},
+		{dbtype.Date(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), true},
+		{dbtype.LocalDateTime(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)), true},
+		{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"01/05/2024", false},
+		{"2024-5-1", false},
+		{"2024-13-01", false},
+		{20240501, false},
+	}
+
+	for _, test := range tests {
+		err := registry.CheckRelation("BUYS_FROM_RETAILER", consumer, retailer, map[string]any{"productId": "P1", "date": test.date})
+		if test.valid && err != nil {
+			t.Errorf("date %v: got %v, want it accepted", test.date, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("date %v: got nil, want it rejected", test.date)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// GraphSchema describes the labels and relationship types found in the graph.
+type GraphSchema struct {
+	Labels    []LabelSummary
+	Relations []RelationSummary
+}
+
+// LabelSummary describes the nodes with Label. Properties are observed in a
+// sample of Sampled nodes.
+type LabelSummary struct {
+	Label      string
+	Count      int64
+	Sampled    int
+	Properties []PropertySummary
+}
+
+// RelationSummary describes the relationships of Type and the labels of the
+// nodes they connect. Endpoints and Properties are observed in a sample of
+// Sampled relationships.
+type RelationSummary struct {
+	Type       string
+	Count      int64
+	Endpoints  []LabelPair
+	Sampled    int
+	Properties []PropertySummary
+}
+
+// LabelPair is the label of the start and end node of a relationship.
+type LabelPair struct {
+	From string
+	To   string
+}
+
+// PropertySummary tells which types a property key was seen with and how
+// many sampled elements have it.
+type PropertySummary struct {
+	Key         string
+	Types       []string
+	Occurrences int
+}
+
+// SummarizeProperties returns the keys found in samples sorted by name.
+func SummarizeProperties(samples []map[string]any) []PropertySummary {
+	byKey := make(map[string]*PropertySummary)
+	for _, properties := range samples {
+		for key, value := range properties {
+			summary, found := byKey[key]
+			if !found {
+				summary = &PropertySummary{Key: key}
+				byKey[key] = summary
+			}
+			summary.Occurrences++
+			if valueType := ValueType(value); !slices.Contains(summary.Types, valueType) {
+				summary.Types = append(summary.Types, valueType)
+			}
+		}
+	}
+
+	summaries := make([]PropertySummary, 0, len(byKey))
+	for _, summary := range byKey {
+		sort.Strings(summary.Types)
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Key < summaries[j].Key })
+	return summaries
+}
+
+// ValueType names the Cypher type of a property value, as `valueType()` does.
+func ValueType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "STRING"
+	case bool:
+		return "BOOLEAN"
+	case int, int8, int16, int32, int64:
+		return "INTEGER"
+	case float32, float64:
+		return "FLOAT"
+	case []byte:
+		return "BYTE ARRAY"
+	case []any, []string, []int64, []float64, []bool:
+		return "LIST"
+	case dbtype.Date:
+		return "DATE"
+	case dbtype.LocalTime:
+		return "LOCAL TIME"
+	case dbtype.Time:
+		return "ZONED TIME"
+	case dbtype.LocalDateTime:
+		return "LOCAL DATETIME"
+	case time.Time:
+		return "ZONED DATETIME"
+	case dbtype.Duration:
+		return "DURATION"
+	case dbtype.Point2D, dbtype.Point3D:
+		return "POINT"
+	}
+	return "ANY"
+}
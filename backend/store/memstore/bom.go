@@ -0,0 +1,62 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// BillOfMaterials walks the `NEEDS` relationships breadth first, every
+// material is expanded once so cycles end the walk.
+func (s *Store) BillOfMaterials(ctx context.Context, productId string, depth int) (store.BillOfMaterials, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var product *store.Node
+	for _, node := range s.nodes {
+		if isProduct(node, productId) {
+			product = node
+			break
+		}
+	}
+	if product == nil {
+		return store.BillOfMaterials{}, store.ErrNotFound
+	}
+
+	bom := store.BillOfMaterials{
+		Product:   cloneNode(product),
+		Materials: []store.Node{},
+		Needs:     []store.Relationship{},
+		Providers: make(map[string][]store.Node),
+	}
+	expanded := map[string]bool{product.ElementId: true}
+	frontier := []string{product.ElementId}
+	for level := 0; level < depth && len(frontier) > 0; level++ {
+		var next []string
+		for _, relation := range s.relations {
+			if relation.Type != "NEEDS" || !slices.Contains(frontier, relation.StartElementId) {
+				continue
+			}
+			material := s.nodeIndex[relation.EndElementId]
+			if !hasLabel(material, "Material") {
+				continue
+			}
+			bom.Needs = append(bom.Needs, cloneRelation(relation))
+			if !expanded[material.ElementId] {
+				expanded[material.ElementId] = true
+				bom.Materials = append(bom.Materials, cloneNode(material))
+				next = append(next, material.ElementId)
+			}
+		}
+		frontier = next
+	}
+
+	for _, relation := range s.relations {
+		provider := s.nodeIndex[relation.StartElementId]
+		if relation.Type == "PRODUCES" && hasLabel(provider, "Provider") && expanded[relation.EndElementId] {
+			bom.Providers[relation.EndElementId] = append(bom.Providers[relation.EndElementId], cloneNode(provider))
+		}
+	}
+	return bom, nil
+}
@@ -0,0 +1,112 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) GetNode(ctx context.Context, elementId string) (store.Node, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	node, found := s.nodeIndex[elementId]
+	if !found {
+		return store.Node{}, store.ErrNotFound
+	}
+	return cloneNode(node), nil
+}
+
+func (s *Store) UpdateNode(ctx context.Context, elementId string, properties map[string]any) (store.NodeUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, found := s.nodeIndex[elementId]
+	if !found {
+		return store.NodeUpdate{}, store.ErrNotFound
+	}
+
+	before := cloneProperties(node.Props)
+	setProperties(node.Props, properties)
+	return store.NodeUpdate{Node: cloneNode(node), Before: before, After: cloneProperties(node.Props)}, nil
+}
+
+func (s *Store) DeleteNode(ctx context.Context, elementId string) (store.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, found := s.nodeIndex[elementId]
+	if !found {
+		return store.Node{}, store.ErrNotFound
+	}
+	return s.removeNodes([]*store.Node{node})[0], nil
+}
+
+func (s *Store) GetRelation(ctx context.Context, elementId string) (store.RelationMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	relation := s.relationByElementId(elementId)
+	if relation == nil {
+		return store.RelationMatch{}, store.ErrNotFound
+	}
+	return store.RelationMatch{
+		Relation:    cloneRelation(relation),
+		Origin:      cloneNode(s.nodeIndex[relation.StartElementId]),
+		Destination: cloneNode(s.nodeIndex[relation.EndElementId]),
+	}, nil
+}
+
+func (s *Store) CreateRelationBetween(ctx context.Context, originId string, destinationId string, relationType string, properties map[string]any) (store.RelationMatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	origin, originFound := s.nodeIndex[originId]
+	destination, destinationFound := s.nodeIndex[destinationId]
+	if !originFound || !destinationFound {
+		return store.RelationMatch{}, store.ErrNotFound
+	}
+
+	relation := s.insertRelation(relationType, origin, destination, properties)
+	return store.RelationMatch{
+		Relation:    cloneRelation(relation),
+		Origin:      cloneNode(origin),
+		Destination: cloneNode(destination),
+	}, nil
+}
+
+func (s *Store) UpdateRelation(ctx context.Context, elementId string, properties map[string]any) (store.Relationship, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	relation := s.relationByElementId(elementId)
+	if relation == nil {
+		return store.Relationship{}, store.ErrNotFound
+	}
+	setProperties(relation.Props, properties)
+	return cloneRelation(relation), nil
+}
+
+func (s *Store) DeleteRelation(ctx context.Context, elementId string) (store.Relationship, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	relation := s.relationByElementId(elementId)
+	if relation == nil {
+		return store.Relationship{}, store.ErrNotFound
+	}
+	deleted := cloneRelation(relation)
+	s.deleteRelations(func(candidate *store.Relationship) bool {
+		return candidate == relation
+	})
+	return deleted, nil
+}
+
+func (s *Store) relationByElementId(elementId string) *store.Relationship {
+	for _, relation := range s.relations {
+		if relation.ElementId == elementId {
+			return relation
+		}
+	}
+	return nil
+}
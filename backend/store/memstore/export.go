@@ -0,0 +1,55 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// Export copies the selected elements while holding the lock, so the
+// callbacks run without blocking other requests.
+func (s *Store) Export(ctx context.Context, filter store.ExportFilter, onNode func(store.Node) error, onRelation func(store.Relationship) error) error {
+	nodes, relations := s.exportSnapshot(filter)
+
+	for _, node := range nodes {
+		if err := onNode(node); err != nil {
+			return err
+		}
+	}
+	for _, relation := range relations {
+		if err := onRelation(relation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) exportSnapshot(filter store.ExportFilter) ([]store.Node, []store.Relationship) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	exported := func(node *store.Node) bool {
+		return len(filter.Labels) == 0 || slices.ContainsFunc(node.Labels, func(label string) bool {
+			return slices.Contains(filter.Labels, label)
+		})
+	}
+
+	var nodes []store.Node
+	for _, node := range s.nodes {
+		if exported(node) {
+			nodes = append(nodes, cloneNode(node))
+		}
+	}
+
+	var relations []store.Relationship
+	for _, relation := range s.relations {
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, relation.Type) {
+			continue
+		}
+		if exported(s.nodeIndex[relation.StartElementId]) && exported(s.nodeIndex[relation.EndElementId]) {
+			relations = append(relations, cloneRelation(relation))
+		}
+	}
+	return nodes, relations
+}
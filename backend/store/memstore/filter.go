@@ -0,0 +1,88 @@
+package memstore
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+)
+
+// exprMatches evaluates a filter expression over properties, a nil
+// expression matches everything.
+func exprMatches(properties map[string]any, expr filter.Expr) bool {
+	switch e := expr.(type) {
+	case nil:
+		return true
+	case filter.And:
+		for _, expr := range e {
+			if !exprMatches(properties, expr) {
+				return false
+			}
+		}
+		return true
+	case filter.Or:
+		for _, expr := range e {
+			if exprMatches(properties, expr) {
+				return true
+			}
+		}
+		return false
+	case filter.Condition:
+		return conditionMatches(properties, e)
+	}
+	return false
+}
+
+// conditionMatches follows Cypher: comparing a missing property, or values of
+// different types, is never true.
+func conditionMatches(properties map[string]any, condition filter.Condition) bool {
+	value, found := properties[condition.Key]
+	if condition.Op == filter.OpExists {
+		return found == condition.Value.(bool)
+	}
+	if !found || value == nil {
+		return false
+	}
+
+	switch condition.Op {
+	case filter.OpEq:
+		return valuesEqual(value, condition.Value)
+	case filter.OpNe:
+		return !valuesEqual(value, condition.Value)
+	case filter.OpGt, filter.OpGte, filter.OpLt, filter.OpLte:
+		if typeRank(value) != typeRank(condition.Value) {
+			return false
+		}
+		order := compareValues(value, condition.Value)
+		switch condition.Op {
+		case filter.OpGt:
+			return order > 0
+		case filter.OpGte:
+			return order >= 0
+		case filter.OpLt:
+			return order < 0
+		}
+		return order <= 0
+	case filter.OpIn:
+		return slices.ContainsFunc(condition.Value.([]any), func(candidate any) bool {
+			return valuesEqual(value, candidate)
+		})
+	case filter.OpContains, filter.OpStartsWith, filter.OpRegex:
+		text, isString := value.(string)
+		if !isString {
+			return false
+		}
+		pattern := condition.Value.(string)
+		switch condition.Op {
+		case filter.OpContains:
+			return strings.Contains(text, pattern)
+		case filter.OpStartsWith:
+			return strings.HasPrefix(text, pattern)
+		}
+		// Cypher's `=~` must match the whole string.
+		matched, _ := regexp.MatchString("^(?:"+pattern+")$", text)
+		return matched
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package memstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// Fixture is the JSON document used to seed the graph.
+//
+// Relations reference nodes by their fixture `key`, which isn't stored as a property:
+//
+//	{
+//		"nodes": [
+//			{"key": "acme", "labels": ["Provider"], "properties": {"id": "PR1", "name": "Acme"}},
+//			{"key": "bolt", "labels": ["Product"], "properties": {"id": "P1", "name": "Bolt"}}
+//		],
+//		"relations": [
+//			{"type": "PRODUCES", "from": "acme", "to": "bolt", "properties": {}}
+//		]
+//	}
+type Fixture struct {
+	Nodes     []FixtureNode     `json:"nodes"`
+	Relations []FixtureRelation `json:"relations"`
+}
+
+type FixtureNode struct {
+	Key        string         `json:"key"`
+	Labels     []string       `json:"labels"`
+	Properties map[string]any `json:"properties"`
+}
+
+type FixtureRelation struct {
+	Type       string         `json:"type"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Properties map[string]any `json:"properties"`
+}
+
+// LoadFixture seeds the graph with the fixture stored at path.
+func (s *Store) LoadFixture(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.Seed(file)
+}
+
+// Seed decodes a fixture from r and adds its nodes and relations to the graph.
+// Nothing is added if the fixture is invalid.
+func (s *Store) Seed(r io.Reader) error {
+	var fixture Fixture
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return fmt.Errorf("invalid fixture: %w", err)
+	}
+
+	keys := make(map[string]bool, len(fixture.Nodes))
+	for i, node := range fixture.Nodes {
+		if node.Key == "" {
+			return fmt.Errorf("invalid fixture: node %d has no `key`", i)
+		}
+		if keys[node.Key] {
+			return fmt.Errorf("invalid fixture: node key `%s` is repeated", node.Key)
+		}
+		keys[node.Key] = true
+	}
+	for i, relation := range fixture.Relations {
+		if relation.Type == "" {
+			return fmt.Errorf("invalid fixture: relation %d has no `type`", i)
+		}
+		if !keys[relation.From] || !keys[relation.To] {
+			return fmt.Errorf("invalid fixture: relation %d references an unknown node", i)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nodes := make(map[string]*store.Node, len(fixture.Nodes))
+	for _, node := range fixture.Nodes {
+		nodes[node.Key] = s.insertNode(node.Labels, node.Properties)
+	}
+	for _, relation := range fixture.Relations {
+		s.insertRelation(relation.Type, nodes[relation.From], nodes[relation.To], relation.Properties)
+	}
+	return nil
+}
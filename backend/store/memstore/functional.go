@@ -0,0 +1,218 @@
+package memstore
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) ProductHistory(ctx context.Context, productId string) ([]store.Path, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := []store.Path{}
+	for _, produces := range s.relations {
+		if produces.Type != "PRODUCES" {
+			continue
+		}
+		provider := s.nodeIndex[produces.StartElementId]
+		target := s.nodeIndex[produces.EndElementId]
+		if !hasLabel(provider, "Provider") {
+			continue
+		}
+
+		// (:Provider)-[:PRODUCES]->(:Product {id: $id})
+		if isProduct(target, productId) {
+			paths = append(paths, store.Path{
+				Nodes:         []store.Node{cloneNode(provider), cloneNode(target)},
+				Relationships: []store.Relationship{cloneRelation(produces)},
+			})
+			continue
+		}
+
+		// (:Provider)-[:PRODUCES]->(:Material)<-[:NEEDS]-(:Product {id: $id})
+		if !hasLabel(target, "Material") {
+			continue
+		}
+		for _, needs := range s.relations {
+			if needs.Type != "NEEDS" || needs.EndElementId != target.ElementId {
+				continue
+			}
+			product := s.nodeIndex[needs.StartElementId]
+			if !isProduct(product, productId) {
+				continue
+			}
+			paths = append(paths, store.Path{
+				Nodes:         []store.Node{cloneNode(provider), cloneNode(target), cloneNode(product)},
+				Relationships: []store.Relationship{cloneRelation(produces), cloneRelation(needs)},
+			})
+		}
+	}
+	return paths, nil
+}
+
+// TopProducts mirrors:
+//
+//	MATCH (c:Consumer)-[r:RATES]->(p:Product)
+//	WHERE p.category = $category AND $window
+//	RETURN p.name AS name, AVG(r.rating) AS average_rating
+func (s *Store) TopProducts(ctx context.Context, filter store.StatisticsFilter) ([]store.ProductRating, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type ratings struct {
+		sum   float64
+		count int
+	}
+	groups := newGroups[string, ratings]()
+
+	for _, rates := range s.relations {
+		consumer := s.nodeIndex[rates.StartElementId]
+		product := s.nodeIndex[rates.EndElementId]
+		if rates.Type != "RATES" || !hasLabel(consumer, "Consumer") || !hasLabel(product, "Product") {
+			continue
+		}
+		if !inCategory(product, filter) || !filter.InWindow(rates.Props["date"]) {
+			continue
+		}
+		rating, isNumber := toFloat(rates.Props["rating"])
+		group := groups.get(stringProperty(product, "name"))
+		if isNumber {
+			group.sum += rating
+			group.count++
+		}
+	}
+
+	result := []store.ProductRating{}
+	for _, name := range groups.keys {
+		group := groups.values[name]
+		average := 0.0
+		if group.count > 0 {
+			average = group.sum / float64(group.count)
+		}
+		result = append(result, store.ProductRating{Name: name, AverageRating: average})
+	}
+	return top(result, filter.LimitOr(store.TopProductsLimit), func(a, b store.ProductRating) int {
+		return cmp.Compare(b.AverageRating, a.AverageRating)
+	}), nil
+}
+
+// TopProviders mirrors:
+//
+//	MATCH (p:Provider)<-[r:PREFERS]-(c:Retailer)
+//	WHERE p.country = $country
+//	RETURN p.name AS name, COUNT(r) AS popularity
+func (s *Store) TopProviders(ctx context.Context, filter store.StatisticsFilter) ([]store.ProviderPopularity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := newGroups[string, int64]()
+
+	for _, prefers := range s.relations {
+		retailer := s.nodeIndex[prefers.StartElementId]
+		provider := s.nodeIndex[prefers.EndElementId]
+		if prefers.Type != "PREFERS" || !hasLabel(retailer, "Retailer") || !hasLabel(provider, "Provider") {
+			continue
+		}
+		if filter.Country != "" && !valuesEqual(provider.Props["country"], filter.Country) {
+			continue
+		}
+		*groups.get(stringProperty(provider, "name"))++
+	}
+
+	result := []store.ProviderPopularity{}
+	for _, name := range groups.keys {
+		result = append(result, store.ProviderPopularity{Name: name, Popularity: *groups.values[name]})
+	}
+	return top(result, filter.LimitOr(store.TopProvidersLimit), func(a, b store.ProviderPopularity) int {
+		return cmp.Compare(b.Popularity, a.Popularity)
+	}), nil
+}
+
+// TopPurchasedProducts mirrors:
+//
+//	MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer),
+//	      (p:Product {id: r.productId})
+//	WHERE p.category = $category AND $window
+//	RETURN p.name AS product_name, r.productId AS product_id, COUNT(r) AS purchases
+func (s *Store) TopPurchasedProducts(ctx context.Context, filter store.StatisticsFilter) ([]store.PurchasedProduct, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type key struct{ name, id string }
+	groups := newGroups[key, int64]()
+
+	for _, buys := range s.relations {
+		consumer := s.nodeIndex[buys.StartElementId]
+		retailer := s.nodeIndex[buys.EndElementId]
+		if buys.Type != "BUYS_FROM_RETAILER" || !hasLabel(consumer, "Consumer") || !hasLabel(retailer, "Retailer") {
+			continue
+		}
+		productId, found := buys.Props["productId"]
+		if !found || !filter.InWindow(buys.Props["date"]) {
+			continue
+		}
+		for _, product := range s.nodes {
+			if hasLabel(product, "Product") && valuesEqual(product.Props["id"], productId) && inCategory(product, filter) {
+				id, _ := productId.(string)
+				*groups.get(key{name: stringProperty(product, "name"), id: id})++
+			}
+		}
+	}
+
+	result := []store.PurchasedProduct{}
+	for _, k := range groups.keys {
+		result = append(result, store.PurchasedProduct{ProductName: k.name, ProductId: k.id, Purchases: *groups.values[k]})
+	}
+	return top(result, filter.LimitOr(store.TopPurchasedProductsLimit), func(a, b store.PurchasedProduct) int {
+		return cmp.Compare(b.Purchases, a.Purchases)
+	}), nil
+}
+
+// groups is an aggregation that remembers the order in which keys first appeared.
+type groups[K comparable, V any] struct {
+	keys   []K
+	values map[K]*V
+}
+
+func newGroups[K comparable, V any]() *groups[K, V] {
+	return &groups[K, V]{values: make(map[K]*V)}
+}
+
+func (g *groups[K, V]) get(key K) *V {
+	value, found := g.values[key]
+	if !found {
+		value = new(V)
+		g.values[key] = value
+		g.keys = append(g.keys, key)
+	}
+	return value
+}
+
+// top sorts items with compare and keeps the first limit.
+func top[T any](items []T, limit int, compare func(a, b T) int) []T {
+	slices.SortStableFunc(items, compare)
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+func hasLabel(node *store.Node, label string) bool {
+	return node != nil && slices.Contains(node.Labels, label)
+}
+
+func isProduct(node *store.Node, productId string) bool {
+	return hasLabel(node, "Product") && valuesEqual(node.Props["id"], productId)
+}
+
+func inCategory(product *store.Node, filter store.StatisticsFilter) bool {
+	return filter.Category == "" || valuesEqual(product.Props["category"], filter.Category)
+}
+
+func stringProperty(node *store.Node, key string) string {
+	value, _ := node.Props[key].(string)
+	return value
+}
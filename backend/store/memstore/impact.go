@@ -0,0 +1,98 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// Impact mirrors the neo4jstore patterns, walking up to maxDepth `NEEDS`
+// relationships backwards from what the source produces, or from the source
+// material.
+func (s *Store) Impact(ctx context.Context, label string, id string, maxDepth int) (store.Impact, error) {
+	if label != "Provider" && label != "Material" {
+		return store.Impact{}, fmt.Errorf("no impact analysis for `%s` nodes", label)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var source *store.Node
+	for _, node := range s.nodes {
+		if hasLabel(node, label) && valuesEqual(node.Props["id"], id) {
+			source = node
+			break
+		}
+	}
+	if source == nil {
+		return store.Impact{}, store.ErrNotFound
+	}
+
+	impact := store.Impact{
+		Source:    cloneNode(source),
+		Materials: []store.Node{},
+		Products:  []store.Node{},
+		Retailers: []store.Node{},
+		Consumers: []store.Node{},
+	}
+
+	affected := make(map[string]bool)
+	var frontier []string
+	visit := func(node *store.Node) {
+		if node == source || affected[node.ElementId] {
+			return
+		}
+		affected[node.ElementId] = true
+		frontier = append(frontier, node.ElementId)
+		switch {
+		case hasLabel(node, "Product"):
+			impact.Products = append(impact.Products, cloneNode(node))
+		case hasLabel(node, "Material"):
+			impact.Materials = append(impact.Materials, cloneNode(node))
+		}
+	}
+
+	if label == "Provider" {
+		for _, relation := range s.relations {
+			if relation.Type == "PRODUCES" && relation.StartElementId == source.ElementId {
+				visit(s.nodeIndex[relation.EndElementId])
+			}
+		}
+	} else {
+		frontier = []string{source.ElementId}
+	}
+
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		current := frontier
+		frontier = nil
+		for _, relation := range s.relations {
+			if relation.Type == "NEEDS" && slices.Contains(current, relation.EndElementId) {
+				visit(s.nodeIndex[relation.StartElementId])
+			}
+		}
+	}
+
+	retailers, consumers := make(map[string]bool), make(map[string]bool)
+	for _, purchase := range s.relations {
+		if purchase.Type != "BUYS_FROM_RETAILER" || !slices.ContainsFunc(impact.Products, func(product store.Node) bool {
+			return valuesEqual(product.Props["id"], purchase.Props["productId"])
+		}) {
+			continue
+		}
+		consumer, retailer := s.nodeIndex[purchase.StartElementId], s.nodeIndex[purchase.EndElementId]
+		if !hasLabel(consumer, "Consumer") || !hasLabel(retailer, "Retailer") {
+			continue
+		}
+		if !retailers[retailer.ElementId] {
+			retailers[retailer.ElementId] = true
+			impact.Retailers = append(impact.Retailers, cloneNode(retailer))
+		}
+		if !consumers[consumer.ElementId] {
+			consumers[consumer.ElementId] = true
+			impact.Consumers = append(impact.Consumers, cloneNode(consumer))
+		}
+	}
+	return impact, nil
+}
@@ -0,0 +1,37 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) ImportNodes(ctx context.Context, label string, rows []store.ImportRow) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, row := range rows {
+		s.insertNode([]string{label}, row.Properties)
+	}
+	return len(rows), nil
+}
+
+func (s *Store) ImportRelations(ctx context.Context, spec store.RelationImport, rows []store.ImportRow) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imported := []int{}
+	for _, row := range rows {
+		origins := s.matchNodes(store.Object{Category: spec.Origin.Label, Properties: map[string]any{spec.Origin.Key: row.From}}, 0)
+		destinations := s.matchNodes(store.Object{Category: spec.Destination.Label, Properties: map[string]any{spec.Destination.Key: row.To}}, 0)
+		for _, origin := range origins {
+			for _, destination := range destinations {
+				s.insertRelation(spec.Type, origin, destination, row.Properties)
+			}
+		}
+		if len(origins) > 0 && len(destinations) > 0 {
+			imported = append(imported, row.Line)
+		}
+	}
+	return imported, nil
+}
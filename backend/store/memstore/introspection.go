@@ -0,0 +1,84 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) Introspect(ctx context.Context, sampleSize int) (store.GraphSchema, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var labels []string
+	labelCounts := make(map[string]int64)
+	labelSamples := make(map[string][]map[string]any)
+	for _, node := range s.nodes {
+		for _, label := range node.Labels {
+			if _, found := labelCounts[label]; !found {
+				labels = append(labels, label)
+			}
+			labelCounts[label]++
+			if len(labelSamples[label]) < sampleSize {
+				labelSamples[label] = append(labelSamples[label], node.Props)
+			}
+		}
+	}
+
+	var types []string
+	typeCounts := make(map[string]int64)
+	typeSamples := make(map[string][]map[string]any)
+	endpoints := make(map[string][]store.LabelPair)
+	for _, relation := range s.relations {
+		if _, found := typeCounts[relation.Type]; !found {
+			types = append(types, relation.Type)
+		}
+		typeCounts[relation.Type]++
+		if len(typeSamples[relation.Type]) >= sampleSize {
+			continue
+		}
+		typeSamples[relation.Type] = append(typeSamples[relation.Type], relation.Props)
+		for _, from := range s.nodeIndex[relation.StartElementId].Labels {
+			for _, to := range s.nodeIndex[relation.EndElementId].Labels {
+				pair := store.LabelPair{From: from, To: to}
+				if !slices.Contains(endpoints[relation.Type], pair) {
+					endpoints[relation.Type] = append(endpoints[relation.Type], pair)
+				}
+			}
+		}
+	}
+
+	schema := store.GraphSchema{
+		Labels:    make([]store.LabelSummary, 0, len(labels)),
+		Relations: make([]store.RelationSummary, 0, len(types)),
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		schema.Labels = append(schema.Labels, store.LabelSummary{
+			Label:      label,
+			Count:      labelCounts[label],
+			Sampled:    len(labelSamples[label]),
+			Properties: store.SummarizeProperties(labelSamples[label]),
+		})
+	}
+	slices.Sort(types)
+	for _, relType := range types {
+		pairs := endpoints[relType]
+		slices.SortFunc(pairs, func(a, b store.LabelPair) int {
+			if a.From != b.From {
+				return strings.Compare(a.From, b.From)
+			}
+			return strings.Compare(a.To, b.To)
+		})
+		schema.Relations = append(schema.Relations, store.RelationSummary{
+			Type:       relType,
+			Count:      typeCounts[relType],
+			Endpoints:  append([]store.LabelPair{}, pairs...),
+			Sampled:    len(typeSamples[relType]),
+			Properties: store.SummarizeProperties(typeSamples[relType]),
+		})
+	}
+	return schema, nil
+}
@@ -0,0 +1,241 @@
+// Package memstore implements store.GraphStore with an in-memory property graph.
+//
+// It's meant for tests and offline demos, every operation takes a lock over
+// the whole graph and scans it linearly.
+package memstore
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// Store keeps nodes and relationships in insertion order.
+type Store struct {
+	mu        sync.RWMutex
+	lastId    int64
+	nodes     []*store.Node
+	relations []*store.Relationship
+	nodeIndex map[string]*store.Node
+}
+
+var _ store.GraphStore = (*Store)(nil)
+
+// New creates an empty graph.
+func New() *Store {
+	return &Store{nodeIndex: make(map[string]*store.Node)}
+}
+
+// nextId returns a new id, element IDs start with `prefix` like Neo4j's
+// (4 for nodes and 5 for relationships).
+func (s *Store) nextId(prefix int) (int64, string) {
+	s.lastId++
+	return s.lastId, fmt.Sprintf("%d:memstore:%d", prefix, s.lastId)
+}
+
+// insertNode adds a node, the caller must hold the write lock.
+func (s *Store) insertNode(labels []string, properties map[string]any) *store.Node {
+	id, elementId := s.nextId(4)
+	node := &store.Node{
+		Id:        id,
+		ElementId: elementId,
+		Labels:    slices.Clone(labels),
+		Props:     map[string]any{},
+	}
+	setProperties(node.Props, properties)
+	s.nodes = append(s.nodes, node)
+	s.nodeIndex[elementId] = node
+	return node
+}
+
+// insertRelation adds a relationship, the caller must hold the write lock.
+func (s *Store) insertRelation(relType string, start, end *store.Node, properties map[string]any) *store.Relationship {
+	id, elementId := s.nextId(5)
+	relation := &store.Relationship{
+		Id:             id,
+		ElementId:      elementId,
+		StartId:        start.Id,
+		StartElementId: start.ElementId,
+		EndId:          end.Id,
+		EndElementId:   end.ElementId,
+		Type:           relType,
+		Props:          map[string]any{},
+	}
+	setProperties(relation.Props, properties)
+	s.relations = append(s.relations, relation)
+	return relation
+}
+
+// matchNodes returns the nodes matching object, at most limit when positive.
+func (s *Store) matchNodes(object store.Object, limit int) []*store.Node {
+	var matched []*store.Node
+	for _, node := range s.nodes {
+		if limit > 0 && len(matched) >= limit {
+			break
+		}
+		if nodeMatches(node, object) {
+			matched = append(matched, node)
+		}
+	}
+	return matched
+}
+
+// matchRelations returns the relationships matching pattern, at most limit when positive.
+func (s *Store) matchRelations(pattern store.RelationPattern, limit int) []*store.Relationship {
+	var matched []*store.Relationship
+	for _, relation := range s.relations {
+		if limit > 0 && len(matched) >= limit {
+			break
+		}
+		if s.relationMatches(relation, pattern) {
+			matched = append(matched, relation)
+		}
+	}
+	return matched
+}
+
+func (s *Store) relationMatches(relation *store.Relationship, pattern store.RelationPattern) bool {
+	if pattern.Relation.Category != "" && relation.Type != pattern.Relation.Category {
+		return false
+	}
+	if !propertiesMatch(relation.Props, pattern.Relation.Properties) || !exprMatches(relation.Props, pattern.Relation.Where) {
+		return false
+	}
+	return nodeMatches(s.nodeIndex[relation.StartElementId], pattern.Origin) &&
+		nodeMatches(s.nodeIndex[relation.EndElementId], pattern.Destination)
+}
+
+// deleteRelations removes every relationship for which remove returns true.
+func (s *Store) deleteRelations(remove func(*store.Relationship) bool) int {
+	before := len(s.relations)
+	s.relations = slices.DeleteFunc(s.relations, remove)
+	return before - len(s.relations)
+}
+
+func nodeMatches(node *store.Node, object store.Object) bool {
+	if node == nil {
+		return false
+	}
+	if object.Category != "" && !slices.Contains(node.Labels, object.Category) {
+		return false
+	}
+	return propertiesMatch(node.Props, object.Properties) && exprMatches(node.Props, object.Where)
+}
+
+func propertiesMatch(properties map[string]any, expected map[string]any) bool {
+	for key, value := range expected {
+		actual, found := properties[key]
+		if !found || !valuesEqual(actual, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares two property values, numbers are compared by value
+// regardless of their Go type the same way Cypher compares `1 = 1.0`.
+func valuesEqual(a, b any) bool {
+	na, aIsNumber := toFloat(a)
+	nb, bIsNumber := toFloat(b)
+	if aIsNumber && bIsNumber {
+		return na == nb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues orders two property values like Cypher's `ORDER BY`: values
+// of different types are grouped by type and missing values go last.
+func compareValues(a, b any) int {
+	if rank := cmp.Compare(typeRank(a), typeRank(b)); rank != 0 {
+		return rank
+	}
+	switch va := a.(type) {
+	case string:
+		return strings.Compare(va, b.(string))
+	case bool:
+		vb := b.(bool)
+		switch {
+		case va == vb:
+			return 0
+		case !va:
+			return -1
+		}
+		return 1
+	}
+	na, aIsNumber := toFloat(a)
+	nb, _ := toFloat(b)
+	if aIsNumber {
+		return cmp.Compare(na, nb)
+	}
+	return 0
+}
+
+func typeRank(value any) int {
+	if _, isNumber := toFloat(value); isNumber {
+		return 2
+	}
+	switch value.(type) {
+	case string:
+		return 0
+	case bool:
+		return 1
+	case nil:
+		return 3
+	}
+	// Maps and lists go before any other value.
+	return -1
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// setProperties assigns properties to props, a nil value removes the key the
+// same way `SET n.key = null` does.
+func setProperties(props map[string]any, properties map[string]any) {
+	for key, value := range properties {
+		if value == nil {
+			delete(props, key)
+			continue
+		}
+		props[key] = value
+	}
+}
+
+func cloneProperties(properties map[string]any) map[string]any {
+	if properties == nil {
+		return map[string]any{}
+	}
+	return maps.Clone(properties)
+}
+
+func cloneNode(node *store.Node) store.Node {
+	clone := *node
+	clone.Labels = slices.Clone(node.Labels)
+	clone.Props = cloneProperties(node.Props)
+	return clone
+}
+
+func cloneRelation(relation *store.Relationship) store.Relationship {
+	clone := *relation
+	clone.Props = cloneProperties(relation.Props)
+	return clone
+}
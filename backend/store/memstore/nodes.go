@@ -0,0 +1,131 @@
+package memstore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) CreateNode(ctx context.Context, label string, properties map[string]any) (store.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node := s.insertNode([]string{label}, properties)
+	return cloneNode(node), nil
+}
+
+func (s *Store) MergeNode(ctx context.Context, label string, keys map[string]any, properties map[string]any) (store.NodeMerge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if matched := s.matchNodes(store.Object{Category: label, Properties: keys}, 1); len(matched) > 0 {
+		setProperties(matched[0].Props, properties)
+		return store.NodeMerge{Node: cloneNode(matched[0])}, nil
+	}
+
+	node := s.insertNode([]string{label}, keys)
+	setProperties(node.Props, properties)
+	return store.NodeMerge{Node: cloneNode(node), Created: true}, nil
+}
+
+func (s *Store) FindNodes(ctx context.Context, match store.Object, limit int) ([]store.Node, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nodes := []store.Node{}
+	for _, node := range s.matchNodes(match, limit) {
+		nodes = append(nodes, cloneNode(node))
+	}
+	return nodes, nil
+}
+
+func (s *Store) ListNodes(ctx context.Context, listing store.NodeListing) (store.NodePage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := s.matchNodes(listing.Match, 0)
+	if listing.SortBy != "" {
+		// Matches are already in insertion order, which stands for the element ID order.
+		slices.SortStableFunc(matched, func(a, b *store.Node) int {
+			order := compareValues(a.Props[listing.SortBy], b.Props[listing.SortBy])
+			if listing.Descending {
+				return -order
+			}
+			return order
+		})
+	} else if listing.Descending {
+		slices.Reverse(matched)
+	}
+
+	page := store.NodePage{Nodes: []store.Node{}, Total: int64(len(matched))}
+	for i, node := range matched {
+		if i < listing.Skip {
+			continue
+		}
+		if listing.Limit > 0 && len(page.Nodes) >= listing.Limit {
+			break
+		}
+		page.Nodes = append(page.Nodes, cloneNode(node))
+	}
+	return page, nil
+}
+
+func (s *Store) UpdateNodes(ctx context.Context, match store.Object, properties map[string]any, limit int) ([]store.NodeUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updates := []store.NodeUpdate{}
+	for _, node := range s.matchNodes(match, limit) {
+		before := cloneProperties(node.Props)
+		setProperties(node.Props, properties)
+
+		updates = append(updates, store.NodeUpdate{
+			Node:   cloneNode(node),
+			Before: before,
+			After:  cloneProperties(node.Props),
+		})
+	}
+	return updates, nil
+}
+
+func (s *Store) RemoveNodeProperties(ctx context.Context, match store.Object, keys []string, limit int) ([]store.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nodes := []store.Node{}
+	for _, node := range s.matchNodes(match, limit) {
+		for _, key := range keys {
+			delete(node.Props, key)
+		}
+		nodes = append(nodes, cloneNode(node))
+	}
+	return nodes, nil
+}
+
+func (s *Store) DeleteNodes(ctx context.Context, match store.Object, limit int) ([]store.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeNodes(s.matchNodes(match, limit)), nil
+}
+
+// removeNodes deletes the nodes along with their relationships, the caller
+// must hold the write lock.
+func (s *Store) removeNodes(matched []*store.Node) []store.Node {
+	deleted := make(map[string]bool, len(matched))
+	nodes := []store.Node{}
+	for _, node := range matched {
+		deleted[node.ElementId] = true
+		delete(s.nodeIndex, node.ElementId)
+		nodes = append(nodes, cloneNode(node))
+	}
+
+	s.nodes = slices.DeleteFunc(s.nodes, func(node *store.Node) bool {
+		return deleted[node.ElementId]
+	})
+	s.deleteRelations(func(relation *store.Relationship) bool {
+		return deleted[relation.StartElementId] || deleted[relation.EndElementId]
+	})
+	return nodes
+}
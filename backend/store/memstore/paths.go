@@ -0,0 +1,107 @@
+package memstore
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// FindPaths enumerates the simple paths depth first and keeps the cheapest
+// of the shortest, as neo4jstore does.
+func (s *Store) FindPaths(ctx context.Context, query store.PathQuery) ([]store.WeightedPath, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to := s.nodeIndex[query.From], s.nodeIndex[query.To]
+	if from == nil || to == nil {
+		return nil, store.ErrNotFound
+	}
+
+	var paths []store.WeightedPath
+	nodes := []*store.Node{from}
+	var relations []*store.Relationship
+	visited := map[string]bool{from.ElementId: true}
+
+	// The walk is exponential in MaxDepth, so it stops when ctx is done.
+	var walk func(current *store.Node) error
+	walk = func(current *store.Node) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if current == to {
+			paths = append(paths, s.weightedPath(nodes, relations, query.Weight))
+			return nil
+		}
+		if len(relations) == query.MaxDepth {
+			return nil
+		}
+		for _, relation := range s.relations {
+			if len(query.Types) > 0 && !slices.Contains(query.Types, relation.Type) {
+				continue
+			}
+
+			var nextId string
+			switch {
+			case relation.StartElementId == current.ElementId:
+				nextId = relation.EndElementId
+			case relation.EndElementId == current.ElementId && !query.Directed:
+				nextId = relation.StartElementId
+			default:
+				continue
+			}
+			if visited[nextId] {
+				continue
+			}
+
+			next := s.nodeIndex[nextId]
+			visited[nextId] = true
+			nodes, relations = append(nodes, next), append(relations, relation)
+			if err := walk(next); err != nil {
+				return err
+			}
+			nodes, relations = nodes[:len(nodes)-1], relations[:len(relations)-1]
+			delete(visited, nextId)
+		}
+		return nil
+	}
+	if err := walk(from); err != nil {
+		return nil, err
+	}
+
+	// Like Neo4j's `SHORTEST k`, only the paths with the fewest relationships
+	// are compared by their cost.
+	slices.SortStableFunc(paths, func(a, b store.WeightedPath) int {
+		return cmp.Compare(len(a.Path.Relationships), len(b.Path.Relationships))
+	})
+	if query.Weight != "" && len(paths) > store.WeightedPathCandidates {
+		paths = paths[:store.WeightedPathCandidates]
+	}
+
+	slices.SortStableFunc(paths, func(a, b store.WeightedPath) int {
+		return cmp.Or(cmp.Compare(a.Cost, b.Cost), cmp.Compare(len(a.Path.Relationships), len(b.Path.Relationships)))
+	})
+	if query.Limit > 0 && len(paths) > query.Limit {
+		paths = paths[:query.Limit]
+	}
+	return paths, nil
+}
+
+func (s *Store) weightedPath(nodes []*store.Node, relations []*store.Relationship, weight string) store.WeightedPath {
+	path := store.WeightedPath{}
+	for _, node := range nodes {
+		path.Path.Nodes = append(path.Path.Nodes, cloneNode(node))
+	}
+	for _, relation := range relations {
+		path.Path.Relationships = append(path.Path.Relationships, cloneRelation(relation))
+		cost := 1.0
+		if weight != "" {
+			if value, isNumber := toFloat(relation.Props[weight]); isNumber {
+				cost = value
+			}
+		}
+		path.Cost += cost
+	}
+	return path
+}
@@ -0,0 +1,35 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) PreviewNodes(ctx context.Context, match store.Object, limit int, sampleSize int) (store.NodePreview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := s.matchNodes(match, limit)
+	preview := store.NodePreview{Affected: int64(len(matched)), Sample: []store.Node{}}
+	for _, node := range matched[:min(len(matched), sampleSize)] {
+		preview.Sample = append(preview.Sample, cloneNode(node))
+	}
+	return preview, nil
+}
+
+func (s *Store) PreviewRelations(ctx context.Context, pattern store.RelationPattern, limit int, sampleSize int) (store.RelationPreview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := s.matchRelations(pattern, limit)
+	preview := store.RelationPreview{Affected: int64(len(matched)), Sample: []store.RelationMatch{}}
+	for _, relation := range matched[:min(len(matched), sampleSize)] {
+		preview.Sample = append(preview.Sample, store.RelationMatch{
+			Relation:    cloneRelation(relation),
+			Origin:      cloneNode(s.nodeIndex[relation.StartElementId]),
+			Destination: cloneNode(s.nodeIndex[relation.EndElementId]),
+		})
+	}
+	return preview, nil
+}
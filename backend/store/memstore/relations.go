@@ -0,0 +1,93 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) CreateRelation(ctx context.Context, pattern store.RelationPattern) ([]store.RelationMatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	matches := []store.RelationMatch{}
+	for _, origin := range s.matchNodes(pattern.Origin, 0) {
+		for _, destination := range s.matchNodes(pattern.Destination, 0) {
+			relation := s.mergeRelation(origin, destination, pattern.Relation)
+			matches = append(matches, store.RelationMatch{
+				Relation:    cloneRelation(relation),
+				Origin:      cloneNode(origin),
+				Destination: cloneNode(destination),
+			})
+		}
+	}
+	return matches, nil
+}
+
+// mergeRelation returns the relationship between start and end described by
+// object, creating it if it doesn't exist. Like Cypher's MERGE.
+func (s *Store) mergeRelation(start, end *store.Node, object store.Object) *store.Relationship {
+	for _, relation := range s.relations {
+		if relation.StartElementId == start.ElementId &&
+			relation.EndElementId == end.ElementId &&
+			relation.Type == object.Category &&
+			propertiesMatch(relation.Props, object.Properties) {
+			return relation
+		}
+	}
+	return s.insertRelation(object.Category, start, end, object.Properties)
+}
+
+func (s *Store) FindRelations(ctx context.Context, pattern store.RelationPattern, limit int) ([]store.RelationMatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := []store.RelationMatch{}
+	for _, relation := range s.matchRelations(pattern, limit) {
+		matches = append(matches, store.RelationMatch{
+			Relation:    cloneRelation(relation),
+			Origin:      cloneNode(s.nodeIndex[relation.StartElementId]),
+			Destination: cloneNode(s.nodeIndex[relation.EndElementId]),
+		})
+	}
+	return matches, nil
+}
+
+func (s *Store) UpdateRelations(ctx context.Context, pattern store.RelationPattern, properties map[string]any, limit int) ([]store.Relationship, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	relations := []store.Relationship{}
+	for _, relation := range s.matchRelations(pattern, limit) {
+		setProperties(relation.Props, properties)
+		relations = append(relations, cloneRelation(relation))
+	}
+	return relations, nil
+}
+
+func (s *Store) RemoveRelationProperties(ctx context.Context, pattern store.RelationPattern, keys []string) ([]store.Relationship, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	relations := []store.Relationship{}
+	for _, relation := range s.matchRelations(pattern, 0) {
+		for _, key := range keys {
+			delete(relation.Props, key)
+		}
+		relations = append(relations, cloneRelation(relation))
+	}
+	return relations, nil
+}
+
+func (s *Store) DeleteRelations(ctx context.Context, pattern store.RelationPattern, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := make(map[string]bool)
+	for _, relation := range s.matchRelations(pattern, limit) {
+		deleted[relation.ElementId] = true
+	}
+	return s.deleteRelations(func(relation *store.Relationship) bool {
+		return deleted[relation.ElementId]
+	}), nil
+}
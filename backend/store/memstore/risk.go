@@ -0,0 +1,51 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// SupplyNetwork walks the `NEEDS` relationships of every product through
+// materials only, see store.NeededMaterials.
+func (s *Store) SupplyNetwork(ctx context.Context) (store.SupplyNetwork, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	network := store.SupplyNetwork{
+		Products:  []store.Node{},
+		Materials: []store.Node{},
+		Providers: make(map[string][]store.Node),
+		Needs:     make(map[string][]string),
+	}
+	for _, node := range s.nodes {
+		if hasLabel(node, "Material") {
+			network.Materials = append(network.Materials, cloneNode(node))
+			network.Providers[node.ElementId] = []store.Node{}
+		}
+	}
+	for _, relation := range s.relations {
+		provider := s.nodeIndex[relation.StartElementId]
+		materialId := relation.EndElementId
+		if _, isMaterial := network.Providers[materialId]; relation.Type == "PRODUCES" && isMaterial && hasLabel(provider, "Provider") {
+			network.Providers[materialId] = append(network.Providers[materialId], cloneNode(provider))
+		}
+	}
+
+	// NEEDS relationships ending in a material, only those are followed.
+	needs := make(map[string][]string)
+	for _, relation := range s.relations {
+		if _, isMaterial := network.Providers[relation.EndElementId]; relation.Type == "NEEDS" && isMaterial {
+			needs[relation.StartElementId] = append(needs[relation.StartElementId], relation.EndElementId)
+		}
+	}
+
+	for _, product := range s.nodes {
+		if !hasLabel(product, "Product") {
+			continue
+		}
+		network.Products = append(network.Products, cloneNode(product))
+		network.Needs[product.ElementId] = store.NeededMaterials(needs, product.ElementId)
+	}
+	return network, nil
+}
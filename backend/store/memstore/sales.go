@@ -0,0 +1,118 @@
+package memstore
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// DailySales mirrors the neo4jstore query, grouping the purchases in memory.
+func (s *Store) DailySales(ctx context.Context, query store.SalesQuery) ([]store.DailySales, error) {
+	switch query.GroupBy {
+	case "", store.SalesByProduct, store.SalesByRetailer, store.SalesByProvider:
+	default:
+		return nil, fmt.Errorf("purchases can't be grouped by `%s`", query.GroupBy)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type key struct{ day, id, name string }
+	groups := newGroups[key, int64]()
+
+	for _, buys := range s.relations {
+		consumer := s.nodeIndex[buys.StartElementId]
+		retailer := s.nodeIndex[buys.EndElementId]
+		if buys.Type != "BUYS_FROM_RETAILER" || !hasLabel(consumer, "Consumer") || !hasLabel(retailer, "Retailer") {
+			continue
+		}
+		date, isDate := store.DateOf(buys.Props["date"])
+		if !isDate {
+			continue
+		}
+		day := date.Format(store.DateLayout)
+		if (query.Since != "" && day < query.Since) || (query.Until != "" && day > query.Until) {
+			continue
+		}
+
+		var members []*store.Node
+		switch query.GroupBy {
+		case "":
+			*groups.get(key{day: day})++
+			continue
+		case store.SalesByRetailer:
+			members = []*store.Node{retailer}
+		case store.SalesByProduct:
+			members = s.productsWithId(buys.Props["productId"])
+		case store.SalesByProvider:
+			for _, product := range s.productsWithId(buys.Props["productId"]) {
+				for _, provider := range s.productProviders(product) {
+					if !slices.Contains(members, provider) {
+						members = append(members, provider)
+					}
+				}
+			}
+		}
+		for _, member := range members {
+			*groups.get(key{day: day, id: idString(member), name: stringProperty(member, "name")})++
+		}
+	}
+
+	sales := []store.DailySales{}
+	for _, k := range groups.keys {
+		sales = append(sales, store.DailySales{Day: k.day, GroupId: k.id, GroupName: k.name, Sales: *groups.values[k]})
+	}
+	slices.SortStableFunc(sales, func(a, b store.DailySales) int {
+		return cmp.Compare(a.Day, b.Day)
+	})
+	return sales, nil
+}
+
+func (s *Store) productsWithId(productId any) []*store.Node {
+	var products []*store.Node
+	for _, node := range s.nodes {
+		if hasLabel(node, "Product") && productId != nil && valuesEqual(node.Props["id"], productId) {
+			products = append(products, node)
+		}
+	}
+	return products
+}
+
+// productProviders mirrors:
+//
+//	MATCH (provider:Provider)-[:PRODUCES]->()<-[:NEEDS*0..$depth]-(product)
+func (s *Store) productProviders(product *store.Node) []*store.Node {
+	needed := map[string]bool{product.ElementId: true}
+	frontier := []string{product.ElementId}
+	for level := 0; level < store.SalesProviderDepth && len(frontier) > 0; level++ {
+		var next []string
+		for _, relation := range s.relations {
+			if relation.Type == "NEEDS" && slices.Contains(frontier, relation.StartElementId) && !needed[relation.EndElementId] {
+				needed[relation.EndElementId] = true
+				next = append(next, relation.EndElementId)
+			}
+		}
+		frontier = next
+	}
+
+	var providers []*store.Node
+	for _, relation := range s.relations {
+		provider := s.nodeIndex[relation.StartElementId]
+		if relation.Type == "PRODUCES" && needed[relation.EndElementId] && hasLabel(provider, "Provider") && !slices.Contains(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// idString mirrors `toString(n.id)`.
+func idString(node *store.Node) string {
+	id, found := node.Props["id"]
+	if !found || id == nil {
+		return ""
+	}
+	return fmt.Sprint(id)
+}
@@ -0,0 +1,33 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// The in-memory graph has no constraints or indexes, lookups always scan it.
+
+func (s *Store) Constraints(ctx context.Context) ([]store.Constraint, error) {
+	return nil, store.ErrUnsupported
+}
+
+func (s *Store) CreateUniqueConstraint(ctx context.Context, spec store.IndexSpec) (store.SchemaChange, error) {
+	return store.SchemaChange{}, store.ErrUnsupported
+}
+
+func (s *Store) DropConstraint(ctx context.Context, name string) error {
+	return store.ErrUnsupported
+}
+
+func (s *Store) Indexes(ctx context.Context) ([]store.Index, error) {
+	return nil, store.ErrUnsupported
+}
+
+func (s *Store) CreateIndex(ctx context.Context, spec store.IndexSpec) (store.SchemaChange, error) {
+	return store.SchemaChange{}, store.ErrUnsupported
+}
+
+func (s *Store) DropIndex(ctx context.Context, name string) error {
+	return store.ErrUnsupported
+}
@@ -0,0 +1,52 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// WriteTransaction runs fn against a copy of the graph, which replaces the
+// graph only if fn succeeds. The graph stays locked until fn returns.
+func (s *Store) WriteTransaction(ctx context.Context, fn func(tx store.GraphStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := s.snapshot()
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.lastId, s.nodes, s.relations, s.nodeIndex = tx.lastId, tx.nodes, tx.relations, tx.nodeIndex
+	return nil
+}
+
+// ReadTransaction runs fn against a copy of the graph, so writes made
+// meanwhile aren't seen and the ones made by fn are dropped.
+func (s *Store) ReadTransaction(ctx context.Context, fn func(tx store.GraphStore) error) error {
+	s.mu.RLock()
+	tx := s.snapshot()
+	s.mu.RUnlock()
+
+	return fn(tx)
+}
+
+// snapshot deep copies the graph, the caller must hold the lock.
+func (s *Store) snapshot() *Store {
+	copied := &Store{
+		lastId:    s.lastId,
+		nodes:     make([]*store.Node, 0, len(s.nodes)),
+		relations: make([]*store.Relationship, 0, len(s.relations)),
+		nodeIndex: make(map[string]*store.Node, len(s.nodeIndex)),
+	}
+	for _, node := range s.nodes {
+		clone := cloneNode(node)
+		copied.nodes = append(copied.nodes, &clone)
+		copied.nodeIndex[clone.ElementId] = &clone
+	}
+	for _, relation := range s.relations {
+		clone := cloneRelation(relation)
+		copied.relations = append(copied.relations, &clone)
+	}
+	return copied
+}
@@ -0,0 +1,62 @@
+package neo4jstore
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (s *Store) BillOfMaterials(ctx context.Context, productId string, depth int) (store.BillOfMaterials, error) {
+	var bom store.BillOfMaterials
+
+	// Variable length bounds can't be parameters, depth is an int so it's
+	// safe to write it into the query. Cypher never repeats a relationship in
+	// a path, which ends the traversal of cycles. Like memstore, only
+	// materials are followed.
+	q := cypher.New()
+	q.Raw(`MATCH (product:Product {id: ` + q.Param("id", productId) + `})
+OPTIONAL MATCH p = (product)-[:NEEDS*0..` + strconv.Itoa(depth-1) + `]->()-[r:NEEDS]->(material:Material)
+WHERE all(n IN nodes(p)[1..] WHERE n:Material)
+RETURN product, collect(DISTINCT r) AS needs, collect(DISTINCT material) AS materials`)
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return bom, err
+	}
+	record, err := single(result.Records, nil)
+	if err != nil {
+		return bom, err
+	}
+
+	bom.Product, _, err = neo4j.GetRecordValue[neo4j.Node](record, "product")
+	if err != nil {
+		return bom, err
+	}
+	bom.Needs = listOf[neo4j.Relationship](record, "needs")
+	bom.Materials = listOf[neo4j.Node](record, "materials")
+
+	ids := []string{bom.Product.ElementId}
+	for _, material := range bom.Materials {
+		ids = append(ids, material.ElementId)
+	}
+
+	// MATCH (provider:Provider)-[:PRODUCES]->(n)
+	// WHERE elementId(n) IN $ids
+	// RETURN elementId(n) AS id, collect(provider) AS providers
+	q = cypher.New()
+	q.Raw("MATCH (provider:Provider)-[:PRODUCES]->(n) WHERE elementId(n) IN " + q.Param("ids", ids) +
+		" RETURN elementId(n) AS id, collect(provider) AS providers")
+
+	result, err = s.read(ctx, q)
+	if err != nil {
+		return bom, err
+	}
+	bom.Providers = make(map[string][]store.Node, len(result.Records))
+	for _, record := range result.Records {
+		bom.Providers[value[string](record, "id")] = listOf[neo4j.Node](record, "providers")
+	}
+	return bom, nil
+}
@@ -0,0 +1,142 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// matchNodeById starts a query matching the node `n` with the given element ID.
+func matchNodeById(elementId string) *cypher.Query {
+	q := cypher.New().Match(cypher.Node("n", "", nil))
+	return q.Where("elementId(n) = " + q.Param("elementId", elementId))
+}
+
+// matchRelationById starts a query matching the relationship `(n1)-[r]->(n2)` with the given element ID.
+func matchRelationById(elementId string) *cypher.Query {
+	q := cypher.New().Match(cypher.Node("n1", "", nil).To("r", "", nil, cypher.Node("n2", "", nil)))
+	return q.Where("elementId(r) = " + q.Param("elementId", elementId))
+}
+
+func (s *Store) GetNode(ctx context.Context, elementId string) (store.Node, error) {
+	// MATCH (n)
+	// WHERE elementId(n) = $elementId
+	// RETURN n
+	q := matchNodeById(elementId).Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.Node{}, err
+	}
+	return single(collect[neo4j.Node](result, "n"))
+}
+
+func (s *Store) UpdateNode(ctx context.Context, elementId string, properties map[string]any) (store.NodeUpdate, error) {
+	// MATCH (n)
+	// WHERE elementId(n) = $elementId
+	// WITH n, properties(n) AS beforeUpdate
+	// SET n.$key = $value
+	// RETURN n, beforeUpdate, properties(n) AS afterUpdate
+	q := matchNodeById(elementId).
+		With("n", "properties(n) AS beforeUpdate").
+		Set("n", properties).
+		Return("n", "beforeUpdate", "properties(n) AS afterUpdate")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.NodeUpdate{}, err
+	}
+	return single(collectNodeUpdates(result))
+}
+
+func (s *Store) DeleteNode(ctx context.Context, elementId string) (store.Node, error) {
+	// MATCH (n)
+	// WHERE elementId(n) = $elementId
+	// DETACH DELETE n
+	// RETURN n
+	q := matchNodeById(elementId).
+		DetachDelete("n").
+		Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.Node{}, err
+	}
+	return single(collect[neo4j.Node](result, "n"))
+}
+
+func (s *Store) GetRelation(ctx context.Context, elementId string) (store.RelationMatch, error) {
+	// MATCH (n1)-[r]->(n2)
+	// WHERE elementId(r) = $elementId
+	// RETURN r, n1, n2
+	q := matchRelationById(elementId).Return("r", "n1", "n2")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.RelationMatch{}, err
+	}
+	return single(collectRelationMatches(result))
+}
+
+func (s *Store) CreateRelationBetween(ctx context.Context, originId string, destinationId string, relationType string, properties map[string]any) (store.RelationMatch, error) {
+	// MATCH (n1), (n2)
+	// WHERE elementId(n1) = $origin AND elementId(n2) = $destination
+	// CREATE (n1)-[r:$RelationType {$key: $value}]->(n2)
+	// RETURN r, n1, n2
+	q := cypher.New().Match(cypher.Node("n1", "", nil), cypher.Node("n2", "", nil))
+	q = q.Where("elementId(n1) = "+q.Param("origin", originId), "elementId(n2) = "+q.Param("destination", destinationId)).
+		Create(cypher.Node("n1", "", nil).To("r", relationType, properties, cypher.Node("n2", "", nil))).
+		Return("r", "n1", "n2")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.RelationMatch{}, err
+	}
+	return single(collectRelationMatches(result))
+}
+
+func (s *Store) UpdateRelation(ctx context.Context, elementId string, properties map[string]any) (store.Relationship, error) {
+	// MATCH (n1)-[r]->(n2)
+	// WHERE elementId(r) = $elementId
+	// SET r.$key = $value
+	// RETURN r
+	q := matchRelationById(elementId).
+		Set("r", properties).
+		Return("r")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.Relationship{}, err
+	}
+	return single(collect[neo4j.Relationship](result, "r"))
+}
+
+func (s *Store) DeleteRelation(ctx context.Context, elementId string) (store.Relationship, error) {
+	// MATCH (n1)-[r]->(n2)
+	// WHERE elementId(r) = $elementId
+	// DELETE r
+	// RETURN r
+	q := matchRelationById(elementId).
+		Delete("r").
+		Return("r")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.Relationship{}, err
+	}
+	return single(collect[neo4j.Relationship](result, "r"))
+}
+
+// single returns the only value of a lookup by element ID.
+func single[T any](values []T, err error) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	}
+	if len(values) == 0 {
+		return zero, store.ErrNotFound
+	}
+	return values[0], nil
+}
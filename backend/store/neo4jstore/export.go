@@ -0,0 +1,91 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *Store) Export(ctx context.Context, filter store.ExportFilter, onNode func(store.Node) error, onRelation func(store.Relationship) error) error {
+	tx := s.tx
+	if tx == nil {
+		// Both queries share a read transaction so the relationships always
+		// reference exported nodes.
+		session := s.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: s.database, AccessMode: neo4j.AccessModeRead})
+		defer session.Close(ctx)
+
+		var err error
+		tx, err = session.BeginTransaction(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Close(ctx)
+	}
+
+	// MATCH (n)
+	// WHERE any(label IN labels(n) WHERE label IN $labels)
+	// RETURN n
+	nodes := cypher.New().Match(cypher.Node("n", "", nil))
+	nodes = nodes.Where(exportLabels(nodes, "n", filter)...).Return("n")
+	err := stream(ctx, tx, nodes, func(record *neo4j.Record) error {
+		node, _, err := neo4j.GetRecordValue[neo4j.Node](record, "n")
+		if err != nil {
+			return err
+		}
+		return onNode(node)
+	})
+	if err != nil {
+		return err
+	}
+
+	// MATCH (n1)-[r]->(n2)
+	// WHERE type(r) IN $types AND any(label IN labels(n1) WHERE label IN $labels) AND ...
+	// RETURN r
+	relations := cypher.New().Match(cypher.Node("n1", "", nil).To("r", "", nil, cypher.Node("n2", "", nil)))
+	conditions := append(exportLabels(relations, "n1", filter), exportLabels(relations, "n2", filter)...)
+	if len(filter.Types) > 0 {
+		conditions = append(conditions, "type(r) IN "+relations.Param("types", filter.Types))
+	}
+	relations = relations.Where(conditions...).Return("r")
+	return stream(ctx, tx, relations, func(record *neo4j.Record) error {
+		relation, _, err := neo4j.GetRecordValue[neo4j.Relationship](record, "r")
+		if err != nil {
+			return err
+		}
+		return onRelation(relation)
+	})
+}
+
+// exportLabels returns the condition keeping the nodes bound to variable that
+// have one of the exported labels.
+func exportLabels(q *cypher.Query, variable string, filter store.ExportFilter) []string {
+	if len(filter.Labels) == 0 {
+		return nil
+	}
+	return []string{"any(label IN labels(" + variable + ") WHERE label IN " + q.Param("labels", filter.Labels) + ")"}
+}
+
+// stream runs q inside tx and calls onRecord for every record as it arrives,
+// instead of reading the whole result first.
+func stream(ctx context.Context, tx neo4j.ExplicitTransaction, q *cypher.Query, onRecord func(*neo4j.Record) error) error {
+	query, params, err := q.Build()
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("query", query).Msg("Streaming from DB...")
+	log.Debug().Interface("params", params).Msg("Query params")
+	result, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	for result.Next(ctx) {
+		if err := onRecord(result.Record()); err != nil {
+			return err
+		}
+	}
+	return result.Err()
+}
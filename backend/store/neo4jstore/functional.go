@@ -0,0 +1,132 @@
+package neo4jstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (s *Store) ProductHistory(ctx context.Context, productId string) ([]store.Path, error) {
+	q := cypher.New()
+	q.Raw(`MATCH p1=(:Provider)-[:PRODUCES]->(:Product {id: ` + q.Param("id", productId) + `})
+RETURN p1
+UNION
+MATCH p1=(:Provider)-[:PRODUCES]->(:Material)<-[:NEEDS]-(:Product {id: $id})
+RETURN p1`)
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Path](result, "p1")
+}
+
+func (s *Store) TopProducts(ctx context.Context, filter store.StatisticsFilter) ([]store.ProductRating, error) {
+	// MATCH (c:Consumer)-[r:RATES]->(p:Product)
+	// WHERE p.category = $category AND $window
+	// RETURN p.name AS name, AVG(r.rating) AS average_rating
+	// ORDER BY average_rating DESC
+	// LIMIT $limit
+	q := cypher.New().Raw("MATCH (c:Consumer)-[r:RATES]->(p:Product)")
+	q.Where(productConditions(q, filter)...).
+		Return("p.name AS name", "AVG(r.rating) AS average_rating").
+		OrderBy("average_rating DESC").
+		Limit(filter.LimitOr(store.TopProductsLimit))
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error en top productos: %w", err)
+	}
+	ratings := make([]store.ProductRating, 0, len(result.Records))
+	for _, record := range result.Records {
+		ratings = append(ratings, store.ProductRating{
+			Name:          value[string](record, "name"),
+			AverageRating: value[float64](record, "average_rating"),
+		})
+	}
+	return ratings, nil
+}
+
+func (s *Store) TopProviders(ctx context.Context, filter store.StatisticsFilter) ([]store.ProviderPopularity, error) {
+	// MATCH (p:Provider)<-[r:PREFERS]-(c:Retailer)
+	// WHERE p.country = $country
+	// RETURN p.name AS name, COUNT(r) AS popularity
+	// ORDER BY popularity DESC
+	// LIMIT $limit
+	q := cypher.New().Raw("MATCH (p:Provider)<-[r:PREFERS]-(c:Retailer)")
+	if filter.Country != "" {
+		q.Where("p.country = " + q.Param("country", filter.Country))
+	}
+	q.Return("p.name AS name", "COUNT(r) AS popularity").
+		OrderBy("popularity DESC").
+		Limit(filter.LimitOr(store.TopProvidersLimit))
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error en top proveedores: %w", err)
+	}
+	providers := make([]store.ProviderPopularity, 0, len(result.Records))
+	for _, record := range result.Records {
+		providers = append(providers, store.ProviderPopularity{
+			Name:       value[string](record, "name"),
+			Popularity: value[int64](record, "popularity"),
+		})
+	}
+	return providers, nil
+}
+
+func (s *Store) TopPurchasedProducts(ctx context.Context, filter store.StatisticsFilter) ([]store.PurchasedProduct, error) {
+	// MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer),
+	//       (p:Product {id: r.productId})
+	// WHERE p.category = $category AND $window
+	// RETURN p.name AS product_name, r.productId AS product_id, COUNT(r) AS purchases
+	// ORDER BY purchases DESC
+	// LIMIT $limit
+	q := cypher.New().Raw("MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer), (p:Product {id: r.productId})")
+	q.Where(productConditions(q, filter)...).
+		Return("p.name AS product_name", "r.productId AS product_id", "COUNT(r) AS purchases").
+		OrderBy("purchases DESC").
+		Limit(filter.LimitOr(store.TopPurchasedProductsLimit))
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("error en top productos comprados: %w", err)
+	}
+	products := make([]store.PurchasedProduct, 0, len(result.Records))
+	for _, record := range result.Records {
+		products = append(products, store.PurchasedProduct{
+			ProductName: value[string](record, "product_name"),
+			ProductId:   value[string](record, "product_id"),
+			Purchases:   value[int64](record, "purchases"),
+		})
+	}
+	return products, nil
+}
+
+// productConditions filters the product `p` by category and the relationship
+// `r` by the date window. Dates are compared by their `YYYY-MM-DD` prefix, so
+// both date values and strings work.
+func productConditions(q *cypher.Query, filter store.StatisticsFilter) []string {
+	var conditions []string
+	if filter.Category != "" {
+		conditions = append(conditions, "p.category = "+q.Param("category", filter.Category))
+	}
+	if filter.Since != "" {
+		conditions = append(conditions, "left(toString(r.date), 10) >= "+q.Param("since", filter.Since))
+	}
+	if filter.Until != "" {
+		conditions = append(conditions, "left(toString(r.date), 10) <= "+q.Param("until", filter.Until))
+	}
+	return conditions
+}
+
+// value returns the record value under key, or the zero value if it's missing
+// or has another type.
+func value[T any](record *neo4j.Record, key string) T {
+	raw, _ := record.Get(key)
+	v, _ := raw.(T)
+	return v
+}
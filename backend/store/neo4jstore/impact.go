@@ -0,0 +1,86 @@
+package neo4jstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// impactPatterns match every node affected by the source, by label. `%d` is
+// replaced by the maximum amount of `NEEDS` relationships followed.
+var impactPatterns = map[string]string{
+	// What the provider produces and everything that needs it.
+	"Provider": "(source)-[:PRODUCES]->()<-[:NEEDS*0..%d]-(affected)",
+	"Material": "(source)<-[:NEEDS*1..%d]-(affected)",
+}
+
+func (s *Store) Impact(ctx context.Context, label string, id string, maxDepth int) (store.Impact, error) {
+	var impact store.Impact
+
+	pattern, found := impactPatterns[label]
+	if !found {
+		return impact, fmt.Errorf("no impact analysis for `%s` nodes", label)
+	}
+
+	// Variable length bounds can't be parameters, maxDepth is an int so it's
+	// safe to write it into the query.
+	//
+	// MATCH (source:$Label {id: $id})
+	// OPTIONAL MATCH $pattern
+	// WHERE affected <> source
+	// RETURN source, collect(DISTINCT affected) AS affected
+	q := cypher.New()
+	q.Raw("MATCH (source:" + identifier.Escape(label) + " {id: " + q.Param("id", id) + "})" +
+		" OPTIONAL MATCH " + fmt.Sprintf(pattern, maxDepth) + " WHERE affected <> source" +
+		" RETURN source, collect(DISTINCT affected) AS affected")
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return impact, err
+	}
+	record, err := single(result.Records, nil)
+	if err != nil {
+		return impact, err
+	}
+	impact.Source, _, err = neo4j.GetRecordValue[neo4j.Node](record, "source")
+	if err != nil {
+		return impact, err
+	}
+
+	impact.Materials, impact.Products = []store.Node{}, []store.Node{}
+	productIds := []any{}
+	for _, node := range listOf[neo4j.Node](record, "affected") {
+		switch {
+		case slices.Contains(node.Labels, "Product"):
+			impact.Products = append(impact.Products, node)
+			productIds = append(productIds, node.Props["id"])
+		case slices.Contains(node.Labels, "Material"):
+			impact.Materials = append(impact.Materials, node)
+		}
+	}
+
+	// MATCH (consumer:Consumer)-[purchase:BUYS_FROM_RETAILER]->(retailer:Retailer)
+	// WHERE purchase.productId IN $productIds
+	// RETURN collect(DISTINCT retailer) AS retailers, collect(DISTINCT consumer) AS consumers
+	q = cypher.New()
+	q.Raw("MATCH (consumer:Consumer)-[purchase:BUYS_FROM_RETAILER]->(retailer:Retailer)" +
+		" WHERE purchase.productId IN " + q.Param("productIds", productIds) +
+		" RETURN collect(DISTINCT retailer) AS retailers, collect(DISTINCT consumer) AS consumers")
+
+	result, err = s.read(ctx, q)
+	if err != nil {
+		return impact, err
+	}
+	record, err = single(result.Records, nil)
+	if err != nil {
+		return impact, err
+	}
+	impact.Retailers = listOf[neo4j.Node](record, "retailers")
+	impact.Consumers = listOf[neo4j.Node](record, "consumers")
+	return impact, nil
+}
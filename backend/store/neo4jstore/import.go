@@ -0,0 +1,84 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) ImportNodes(ctx context.Context, label string, rows []store.ImportRow) (int, error) {
+	// UNWIND $rows AS row
+	// CREATE (n:$NodeType)
+	// SET n = row.properties
+	// RETURN count(n) AS created
+	q := cypher.New()
+	q = q.Unwind(q.Param("rows", importParams(rows)), "row").
+		Create(cypher.Node("n", label, nil)).
+		Raw("SET n = row.properties").
+		Return("count(n) AS created")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := collect[int64](result, "created")
+	if err != nil || len(created) == 0 {
+		return 0, err
+	}
+	return int(created[0]), nil
+}
+
+func (s *Store) ImportRelations(ctx context.Context, spec store.RelationImport, rows []store.ImportRow) ([]int, error) {
+	// UNWIND $rows AS row
+	// MATCH (n1:$NodeType)
+	// WHERE n1.$key = row.from
+	// MATCH (n2:$NodeType)
+	// WHERE n2.$key = row.to
+	// CREATE (n1)-[r:$RelationType]->(n2)
+	// SET r = row.properties
+	// RETURN DISTINCT row.line AS line
+	q := cypher.New()
+	q = q.Unwind(q.Param("rows", importParams(rows)), "row").
+		Match(cypher.Node("n1", spec.Origin.Label, nil))
+	q = q.Where(q.Property("n1", spec.Origin.Key) + " = row.from").
+		Match(cypher.Node("n2", spec.Destination.Label, nil))
+	q = q.Where(q.Property("n2", spec.Destination.Key) + " = row.to").
+		Create(cypher.Node("n1", "", nil).To("r", spec.Type, nil, cypher.Node("n2", "", nil))).
+		Raw("SET r = row.properties").
+		Return("DISTINCT row.line AS line")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := collect[int64](result, "line")
+	if err != nil {
+		return nil, err
+	}
+	imported := make([]int, 0, len(lines))
+	for _, line := range lines {
+		imported = append(imported, int(line))
+	}
+	return imported, nil
+}
+
+// importParams converts the rows into the list sent as the `$rows` parameter.
+func importParams(rows []store.ImportRow) []any {
+	params := make([]any, 0, len(rows))
+	for _, row := range rows {
+		properties := row.Properties
+		if properties == nil {
+			properties = map[string]any{}
+		}
+		params = append(params, map[string]any{
+			"line":       row.Line,
+			"properties": properties,
+			"from":       row.From,
+			"to":         row.To,
+		})
+	}
+	return params
+}
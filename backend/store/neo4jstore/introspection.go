@@ -0,0 +1,119 @@
+package neo4jstore
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) Introspect(ctx context.Context, sampleSize int) (store.GraphSchema, error) {
+	schema := store.GraphSchema{Labels: []store.LabelSummary{}, Relations: []store.RelationSummary{}}
+
+	labels, err := s.names(ctx, "CALL db.labels() YIELD label RETURN label AS name ORDER BY name")
+	if err != nil {
+		return schema, err
+	}
+	for _, label := range labels {
+		// Labels read from the database are escaped as they are, they may
+		// predate the identifier rules.
+		pattern := "(n:" + identifier.Escape(label) + ")"
+		count, err := s.count(ctx, "MATCH "+pattern+" RETURN count(n) AS count")
+		if err != nil {
+			return schema, err
+		}
+		sample, err := s.sample(ctx, "MATCH "+pattern+" WITH n LIMIT", "RETURN properties(n) AS properties", sampleSize)
+		if err != nil {
+			return schema, err
+		}
+
+		schema.Labels = append(schema.Labels, store.LabelSummary{
+			Label:      label,
+			Count:      count,
+			Sampled:    len(sample),
+			Properties: store.SummarizeProperties(sample),
+		})
+	}
+
+	types, err := s.names(ctx, "CALL db.relationshipTypes() YIELD relationshipType RETURN relationshipType AS name ORDER BY name")
+	if err != nil {
+		return schema, err
+	}
+	for _, relType := range types {
+		pattern := "(a)-[r:" + identifier.Escape(relType) + "]->(b)"
+		count, err := s.count(ctx, "MATCH "+pattern+" RETURN count(r) AS count")
+		if err != nil {
+			return schema, err
+		}
+		// The endpoints and the properties come from the same sample, reading
+		// the endpoints of every relationship scans the whole type.
+		q := cypher.New()
+		q.Raw("MATCH " + pattern + " WITH a, r, b LIMIT " + q.Param("sampleSize", sampleSize) +
+			" RETURN labels(a) AS from, labels(b) AS to, properties(r) AS properties")
+		result, err := s.read(ctx, q)
+		if err != nil {
+			return schema, err
+		}
+
+		sample := make([]map[string]any, 0, len(result.Records))
+		endpoints := []store.LabelPair{}
+		for _, record := range result.Records {
+			sample = append(sample, value[map[string]any](record, "properties"))
+			for _, from := range listOf[string](record, "from") {
+				for _, to := range listOf[string](record, "to") {
+					pair := store.LabelPair{From: from, To: to}
+					if !slices.Contains(endpoints, pair) {
+						endpoints = append(endpoints, pair)
+					}
+				}
+			}
+		}
+		slices.SortFunc(endpoints, func(a, b store.LabelPair) int {
+			return cmp.Or(strings.Compare(a.From, b.From), strings.Compare(a.To, b.To))
+		})
+
+		summary := store.RelationSummary{
+			Type:       relType,
+			Count:      count,
+			Endpoints:  endpoints,
+			Sampled:    len(sample),
+			Properties: store.SummarizeProperties(sample),
+		}
+		schema.Relations = append(schema.Relations, summary)
+	}
+	return schema, nil
+}
+
+// names runs a query returning a `name` column and collects it.
+func (s *Store) names(ctx context.Context, query string) ([]string, error) {
+	result, err := s.read(ctx, cypher.New().Raw(query))
+	if err != nil {
+		return nil, err
+	}
+	return collect[string](result, "name")
+}
+
+// count runs a query returning a single `count` column.
+func (s *Store) count(ctx context.Context, query string) (int64, error) {
+	result, err := s.read(ctx, cypher.New().Raw(query))
+	if err != nil {
+		return 0, err
+	}
+	return single(collect[int64](result, "count"))
+}
+
+// sample runs `match $sampleSize ret`, where ret returns a `properties` column.
+func (s *Store) sample(ctx context.Context, match string, ret string, sampleSize int) ([]map[string]any, error) {
+	q := cypher.New()
+	q.Raw(match + " " + q.Param("sampleSize", sampleSize) + " " + ret)
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[map[string]any](result, "properties")
+}
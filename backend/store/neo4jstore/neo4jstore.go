@@ -0,0 +1,120 @@
+// Package neo4jstore implements store.GraphStore on top of a Neo4j database.
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rs/zerolog/log"
+)
+
+// Store talks to Neo4j through a driver shared by every request.
+type Store struct {
+	driver   neo4j.DriverWithContext
+	database string
+	// tx is set on the stores handed out by WriteTransaction, every query then runs inside it.
+	tx neo4j.ExplicitTransaction
+}
+
+var _ store.GraphStore = (*Store)(nil)
+
+// New creates a store that runs every query against the `neo4j` database.
+func New(driver neo4j.DriverWithContext) *Store {
+	return &Store{driver: driver, database: "neo4j"}
+}
+
+// run builds and executes q in a write transaction.
+func (s *Store) run(ctx context.Context, q *cypher.Query) (*neo4j.EagerResult, error) {
+	return s.execute(ctx, q, neo4j.ExecuteQueryWithWritersRouting())
+}
+
+// read builds and executes q in a read transaction, Neo4j rejects any write it attempts.
+func (s *Store) read(ctx context.Context, q *cypher.Query) (*neo4j.EagerResult, error) {
+	return s.execute(ctx, q, neo4j.ExecuteQueryWithReadersRouting())
+}
+
+func (s *Store) execute(ctx context.Context, q *cypher.Query, routing neo4j.ExecuteQueryConfigurationOption) (*neo4j.EagerResult, error) {
+	query, params, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("query", query).Msg("Querying DB...")
+	log.Debug().Interface("params", params).Msg("Query params")
+	if s.tx != nil {
+		return runInTransaction(ctx, s.tx, query, params)
+	}
+	return neo4j.ExecuteQuery(ctx, s.driver, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithDatabase(s.database), routing)
+}
+
+func asNode(variable string, object store.Object) cypher.Pattern {
+	return cypher.Node(variable, object.Category, object.Properties)
+}
+
+// relationPattern returns the `(n1)-[r]->(n2)` pattern used by every relation query.
+func relationPattern(pattern store.RelationPattern) cypher.Pattern {
+	return asNode("n1", pattern.Origin).Related(
+		cypher.Rel{Variable: "r", Type: pattern.Relation.Category, Properties: pattern.Relation.Properties},
+		asNode("n2", pattern.Destination),
+	)
+}
+
+// nodeWhere adds a `WHERE` clause with the filter of the node bound to variable.
+func nodeWhere(q *cypher.Query, variable string, object store.Object) *cypher.Query {
+	return whereFilters(q, filter.Where(q, variable, object.Where))
+}
+
+// relationWhere adds a `WHERE` clause with the filters of a relationPattern.
+func relationWhere(q *cypher.Query, pattern store.RelationPattern) *cypher.Query {
+	return whereFilters(q,
+		filter.Where(q, "n1", pattern.Origin.Where),
+		filter.Where(q, "r", pattern.Relation.Where),
+		filter.Where(q, "n2", pattern.Destination.Where),
+	)
+}
+
+func whereFilters(q *cypher.Query, filters ...string) *cypher.Query {
+	conditions := make([]string, 0, len(filters))
+	for _, condition := range filters {
+		if condition != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+	return q.Where(conditions...)
+}
+
+// limitMatches restricts the amount of rows bound to `variables` when limit is positive.
+func limitMatches(q *cypher.Query, limit int, variables ...string) *cypher.Query {
+	if limit <= 0 {
+		return q
+	}
+	return q.With(variables...).Limit(limit)
+}
+
+func collect[T neo4j.RecordValue](result *neo4j.EagerResult, key string) ([]T, error) {
+	values := make([]T, 0, len(result.Records))
+	for _, record := range result.Records {
+		value, _, err := neo4j.GetRecordValue[T](record, key)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// listOf reads a list column keeping the items of type T, a null list is read
+// as an empty one.
+func listOf[T any](record *neo4j.Record, key string) []T {
+	raw := value[[]any](record, key)
+	items := make([]T, 0, len(raw))
+	for _, item := range raw {
+		if typed, ok := item.(T); ok {
+			items = append(items, typed)
+		}
+	}
+	return items
+}
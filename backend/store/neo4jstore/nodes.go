@@ -0,0 +1,196 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (s *Store) CreateNode(ctx context.Context, label string, properties map[string]any) (store.Node, error) {
+	// CREATE (n:$NodeType {$key: $value})
+	// RETURN n
+	q := cypher.New().
+		Create(cypher.Node("n", label, properties)).
+		Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.Node{}, err
+	}
+
+	nodes, err := collect[neo4j.Node](result, "n")
+	if err != nil || len(nodes) == 0 {
+		return store.Node{}, err
+	}
+	return nodes[0], nil
+}
+
+func (s *Store) MergeNode(ctx context.Context, label string, keys map[string]any, properties map[string]any) (store.NodeMerge, error) {
+	// MERGE (n:$NodeType {$key: $value})
+	// ON CREATE SET n.$key = $value
+	// ON MATCH SET n.$key = $value
+	// RETURN n
+	q := cypher.New().
+		Merge(cypher.Node("n", label, keys)).
+		OnCreateSet("n", properties).
+		OnMatchSet("n", properties).
+		Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.NodeMerge{}, err
+	}
+
+	node, err := single(collect[neo4j.Node](result, "n"))
+	if err != nil {
+		return store.NodeMerge{}, err
+	}
+	return store.NodeMerge{Node: node, Created: result.Summary.Counters().NodesCreated() > 0}, nil
+}
+
+func (s *Store) FindNodes(ctx context.Context, match store.Object, limit int) ([]store.Node, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// RETURN n
+	// LIMIT $limit
+	q := cypher.New().Match(asNode("n", match))
+	q = nodeWhere(q, "n", match).
+		Return("n").
+		Limit(limit)
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Node](result, "n")
+}
+
+// ListNodes counts the matches and reads the page in the same read
+// transaction, so the total always agrees with the page.
+func (s *Store) ListNodes(ctx context.Context, listing store.NodeListing) (store.NodePage, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// RETURN count(n) AS total
+	count := cypher.New().Match(asNode("n", listing.Match))
+	count = nodeWhere(count, "n", listing.Match).
+		Return("count(n) AS total")
+
+	// MATCH (n:$NodeType {$key: $value})
+	// RETURN n
+	// ORDER BY n.$sortBy, elementId(n)
+	// SKIP $skip
+	// LIMIT $limit
+	q := cypher.New().Match(asNode("n", listing.Match))
+	q = nodeWhere(q, "n", listing.Match).
+		Return("n")
+
+	order := "elementId(n)"
+	if listing.SortBy != "" {
+		order = q.Property("n", listing.SortBy)
+	}
+	if listing.Descending {
+		order += " DESC"
+	}
+	q = q.OrderBy(order, "elementId(n)").
+		Skip(listing.Skip).
+		Limit(listing.Limit)
+
+	var page store.NodePage
+	err := s.readTransaction(ctx, func(tx *Store) error {
+		result, err := tx.read(ctx, count)
+		if err != nil {
+			return err
+		}
+		if page.Total, err = single(collect[int64](result, "total")); err != nil {
+			return err
+		}
+
+		result, err = tx.read(ctx, q)
+		if err != nil {
+			return err
+		}
+		page.Nodes, err = collect[neo4j.Node](result, "n")
+		return err
+	})
+	if err != nil {
+		return store.NodePage{}, err
+	}
+	return page, nil
+}
+
+func (s *Store) UpdateNodes(ctx context.Context, match store.Object, properties map[string]any, limit int) ([]store.NodeUpdate, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// WITH n, properties(n) AS beforeUpdate
+	// SET n.$key = $value
+	// RETURN n, beforeUpdate, properties(n) AS afterUpdate
+	q := cypher.New().Match(asNode("n", match))
+	q = limitMatches(nodeWhere(q, "n", match), limit, "n").
+		With("n", "properties(n) AS beforeUpdate").
+		Set("n", properties).
+		Return("n", "beforeUpdate", "properties(n) AS afterUpdate")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collectNodeUpdates(result)
+}
+
+// collectNodeUpdates reads the `n`, `beforeUpdate` and `afterUpdate` columns.
+func collectNodeUpdates(result *neo4j.EagerResult) ([]store.NodeUpdate, error) {
+	updates := make([]store.NodeUpdate, 0, len(result.Records))
+	for _, record := range result.Records {
+		node, _, err := neo4j.GetRecordValue[neo4j.Node](record, "n")
+		if err != nil {
+			return nil, err
+		}
+		before, _ := record.Get("beforeUpdate")
+		after, _ := record.Get("afterUpdate")
+
+		updates = append(updates, store.NodeUpdate{
+			Node:   node,
+			Before: before.(map[string]any),
+			After:  after.(map[string]any),
+		})
+	}
+	return updates, nil
+}
+
+func (s *Store) RemoveNodeProperties(ctx context.Context, match store.Object, keys []string, limit int) ([]store.Node, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// REMOVE n.$key
+	// RETURN n
+	q := cypher.New().Match(asNode("n", match))
+	q = limitMatches(nodeWhere(q, "n", match), limit, "n").
+		Remove("n", keys...).
+		Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Node](result, "n")
+}
+
+func (s *Store) DeleteNodes(ctx context.Context, match store.Object, limit int) ([]store.Node, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// WITH n
+	// LIMIT $limit
+	// DETACH DELETE n
+	// RETURN n
+	q := cypher.New().Match(asNode("n", match))
+	q = limitMatches(nodeWhere(q, "n", match), limit, "n").
+		DetachDelete("n").
+		Return("n")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Node](result, "n")
+}
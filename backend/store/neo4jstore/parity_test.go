@@ -0,0 +1,269 @@
+package neo4jstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/ElrohirGT/Proyecto1_DB2/store/memstore"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rs/zerolog"
+)
+
+// The parity tests seed the same fixture in memstore and, when
+// NEO4J_TEST_URI is set, in Neo4j, and check that both stores answer the
+// same. The Neo4j database is emptied first, so it must be a disposable one.
+// NEO4J_TEST_USER and NEO4J_TEST_PASSWORD are its credentials.
+
+// supplyFixture has materials needing other materials (M1 -> M2 -> M3), a
+// cycle back to M1, and a product needed by another product (P1 -> P2), whose
+// material M4 must not be followed.
+const supplyFixture = `{
+	"nodes": [
+		{"key": "pr1", "labels": ["Provider"], "properties": {"id": "PR1"}},
+		{"key": "pr2", "labels": ["Provider"], "properties": {"id": "PR2"}},
+		{"key": "m1", "labels": ["Material"], "properties": {"id": "M1"}},
+		{"key": "m2", "labels": ["Material"], "properties": {"id": "M2"}},
+		{"key": "m3", "labels": ["Material"], "properties": {"id": "M3"}},
+		{"key": "m4", "labels": ["Material"], "properties": {"id": "M4"}},
+		{"key": "p1", "labels": ["Product"], "properties": {"id": "P1"}},
+		{"key": "p2", "labels": ["Product"], "properties": {"id": "P2"}}
+	],
+	"relations": [
+		{"type": "PRODUCES", "from": "pr1", "to": "m1", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m3", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m4", "properties": {}},
+		{"type": "PRODUCES", "from": "pr1", "to": "p1", "properties": {}},
+		{"type": "NEEDS", "from": "p1", "to": "m1", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m1", "to": "m2", "properties": {"quantity": 2}},
+		{"type": "NEEDS", "from": "m2", "to": "m3", "properties": {"quantity": 3}},
+		{"type": "NEEDS", "from": "m3", "to": "m1", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p1", "to": "p2", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "p2", "to": "m4", "properties": {"quantity": 1}}
+	]
+}`
+
+// seededStores returns memstore and, when it's configured, Neo4j, both
+// seeded with fixture.
+func seededStores(t *testing.T, fixture string) map[string]store.GraphStore {
+	t.Helper()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	ctx := context.Background()
+
+	memory := memstore.New()
+	if err := memory.Seed(strings.NewReader(fixture)); err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]store.GraphStore{"memory": memory}
+
+	uri := os.Getenv("NEO4J_TEST_URI")
+	if uri == "" {
+		t.Log("NEO4J_TEST_URI isn't set, only memstore is checked")
+		return stores
+	}
+	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(os.Getenv("NEO4J_TEST_USER"), os.Getenv("NEO4J_TEST_PASSWORD"), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close(ctx) })
+
+	db := New(driver)
+	if _, err := neo4j.ExecuteQuery(ctx, driver, "MATCH (n) DETACH DELETE n", nil, neo4j.EagerResultTransformer); err != nil {
+		t.Fatal(err)
+	}
+	if err := seed(ctx, db, fixture); err != nil {
+		t.Fatal(err)
+	}
+	stores["neo4j"] = db
+	return stores
+}
+
+// seed writes a memstore fixture through the GraphStore interface.
+func seed(ctx context.Context, db store.GraphStore, raw string) error {
+	var fixture memstore.Fixture
+	if err := json.Unmarshal([]byte(raw), &fixture); err != nil {
+		return err
+	}
+
+	elementIds := make(map[string]string, len(fixture.Nodes))
+	for _, node := range fixture.Nodes {
+		created, err := db.CreateNode(ctx, node.Labels[0], node.Properties)
+		if err != nil {
+			return err
+		}
+		elementIds[node.Key] = created.ElementId
+	}
+	for _, relation := range fixture.Relations {
+		if _, err := db.CreateRelationBetween(ctx, elementIds[relation.From], elementIds[relation.To], relation.Type, relation.Properties); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertyIds returns the sorted `id` properties of nodes.
+func propertyIds(nodes []store.Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		id, _ := node.Props["id"].(string)
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestBillOfMaterialsParity(t *testing.T) {
+	tests := []struct {
+		depth     int
+		materials []string
+		needs     []string
+	}{
+		{1, []string{"M1"}, []string{"P1->M1"}},
+		{3, []string{"M1", "M2", "M3"}, []string{"M1->M2", "M2->M3", "P1->M1"}},
+		{4, []string{"M1", "M2", "M3"}, []string{"M1->M2", "M2->M3", "M3->M1", "P1->M1"}},
+	}
+
+	for name, db := range seededStores(t, supplyFixture) {
+		for _, test := range tests {
+			bom, err := db.BillOfMaterials(context.Background(), "P1", test.depth)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+
+			ids := make(map[string]string)
+			ids[bom.Product.ElementId] = "P1"
+			for _, material := range bom.Materials {
+				ids[material.ElementId], _ = material.Props["id"].(string)
+			}
+			needs := make([]string, 0, len(bom.Needs))
+			for _, relation := range bom.Needs {
+				needs = append(needs, ids[relation.StartElementId]+"->"+ids[relation.EndElementId])
+			}
+			slices.Sort(needs)
+
+			if got := propertyIds(bom.Materials); !slices.Equal(got, test.materials) {
+				t.Errorf("%s, depth %d: got materials %v, want %v", name, test.depth, got, test.materials)
+			}
+			if !slices.Equal(needs, test.needs) {
+				t.Errorf("%s, depth %d: got needs %v, want %v", name, test.depth, needs, test.needs)
+			}
+			if got := propertyIds(bom.Providers[bom.Product.ElementId]); !slices.Equal(got, []string{"PR1"}) {
+				t.Errorf("%s, depth %d: got product providers %v, want [PR1]", name, test.depth, got)
+			}
+		}
+	}
+}
+
+func TestImpactParity(t *testing.T) {
+	tests := []struct {
+		label     string
+		id        string
+		maxDepth  int
+		materials []string
+		products  []string
+	}{
+		{"Provider", "PR1", 1, []string{"M1", "M3"}, []string{"P1"}},
+		{"Provider", "PR1", 2, []string{"M1", "M2", "M3"}, []string{"P1"}},
+		{"Material", "M3", 1, []string{"M2"}, []string{}},
+		// The cycle leads back to M3, which isn't affected by itself.
+		{"Material", "M3", 3, []string{"M1", "M2"}, []string{"P1"}},
+		// Unlike the bill of materials, the impact reaches P1 through P2.
+		{"Material", "M4", 10, []string{}, []string{"P1", "P2"}},
+	}
+
+	for name, db := range seededStores(t, supplyFixture) {
+		for _, test := range tests {
+			impact, err := db.Impact(context.Background(), test.label, test.id, test.maxDepth)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if got := propertyIds(impact.Materials); !slices.Equal(got, test.materials) {
+				t.Errorf("%s, %s %s up to %d: got materials %v, want %v", name, test.label, test.id, test.maxDepth, got, test.materials)
+			}
+			if got := propertyIds(impact.Products); !slices.Equal(got, test.products) {
+				t.Errorf("%s, %s %s up to %d: got products %v, want %v", name, test.label, test.id, test.maxDepth, got, test.products)
+			}
+		}
+	}
+}
+
+func TestSupplyNetworkParity(t *testing.T) {
+	want := map[string][]string{
+		"P1": {"M1", "M2", "M3"},
+		// P2's material isn't passed on to P1.
+		"P2": {"M4"},
+	}
+
+	for name, db := range seededStores(t, supplyFixture) {
+		network, err := db.SupplyNetwork(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		materials := make(map[string]store.Node)
+		for _, material := range network.Materials {
+			materials[material.ElementId] = material
+		}
+		for _, product := range network.Products {
+			productId, _ := product.Props["id"].(string)
+			var needed []store.Node
+			for _, materialId := range network.Needs[product.ElementId] {
+				needed = append(needed, materials[materialId])
+			}
+			if got := propertyIds(needed); !slices.Equal(got, want[productId]) {
+				t.Errorf("%s: %s needs %v, want %v", name, productId, got, want[productId])
+			}
+		}
+		if len(network.Products) != len(want) {
+			t.Errorf("%s: got %d products, want %d", name, len(network.Products), len(want))
+		}
+	}
+}
+
+// salesFixture has a purchase of P1, which needs a chain of 7 materials, one
+// level deeper than SalesProviderDepth, so PR2 isn't reached.
+const salesFixture = `{
+	"nodes": [
+		{"key": "c1", "labels": ["Consumer"], "properties": {"id": "C1"}},
+		{"key": "r1", "labels": ["Retailer"], "properties": {"id": "R1"}},
+		{"key": "pr1", "labels": ["Provider"], "properties": {"id": "PR1"}},
+		{"key": "pr2", "labels": ["Provider"], "properties": {"id": "PR2"}},
+		{"key": "p1", "labels": ["Product"], "properties": {"id": "P1"}},
+		{"key": "m1", "labels": ["Material"], "properties": {"id": "M1"}},
+		{"key": "m2", "labels": ["Material"], "properties": {"id": "M2"}},
+		{"key": "m3", "labels": ["Material"], "properties": {"id": "M3"}},
+		{"key": "m4", "labels": ["Material"], "properties": {"id": "M4"}},
+		{"key": "m5", "labels": ["Material"], "properties": {"id": "M5"}},
+		{"key": "m6", "labels": ["Material"], "properties": {"id": "M6"}},
+		{"key": "m7", "labels": ["Material"], "properties": {"id": "M7"}}
+	],
+	"relations": [
+		{"type": "BUYS_FROM_RETAILER", "from": "c1", "to": "r1", "properties": {"productId": "P1", "date": "2024-05-01"}},
+		{"type": "PRODUCES", "from": "pr1", "to": "m1", "properties": {}},
+		{"type": "PRODUCES", "from": "pr1", "to": "m6", "properties": {}},
+		{"type": "PRODUCES", "from": "pr2", "to": "m7", "properties": {}},
+		{"type": "NEEDS", "from": "p1", "to": "m1", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m1", "to": "m2", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m2", "to": "m3", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m3", "to": "m4", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m4", "to": "m5", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m5", "to": "m6", "properties": {"quantity": 1}},
+		{"type": "NEEDS", "from": "m6", "to": "m7", "properties": {"quantity": 1}}
+	]
+}`
+
+func TestSalesByProviderParity(t *testing.T) {
+	for name, db := range seededStores(t, salesFixture) {
+		sales, err := db.DailySales(context.Background(), store.SalesQuery{GroupBy: store.SalesByProvider})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(sales) != 1 || sales[0].GroupId != "PR1" || sales[0].Day != "2024-05-01" || sales[0].Sales != 1 {
+			t.Errorf("%s: got %+v, want one sale of PR1 on 2024-05-01", name, sales)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package neo4jstore
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// FindPaths searches the paths with the fewest relationships with `SHORTEST
+// k`, which needs Neo4j 5.21 or later but not the GDS or APOC plugins. Paths
+// aren't enumerated exhaustively, with a weight only the
+// store.WeightedPathCandidates shortest are compared by their cost.
+func (s *Store) FindPaths(ctx context.Context, query store.PathQuery) ([]store.WeightedPath, error) {
+	// MATCH (n)
+	// WHERE elementId(n) IN $ids
+	// RETURN count(n) AS count
+	ids := []string{query.From, query.To}
+	q := cypher.New()
+	q.Raw("MATCH (n) WHERE elementId(n) IN " + q.Param("ids", ids) + " RETURN count(n) AS count")
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	found, err := single(collect[int64](result, "count"))
+	if err != nil {
+		return nil, err
+	}
+	if found < 2 {
+		return nil, store.ErrNotFound
+	}
+
+	types := make([]string, 0, len(query.Types))
+	for _, relType := range query.Types {
+		escaped, err := identifier.RelationType(relType)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, escaped)
+	}
+	relation := "[]"
+	if len(types) > 0 {
+		relation = "[:" + strings.Join(types, "|") + "]"
+	}
+	arrow := "-"
+	if query.Directed {
+		arrow = "->"
+	}
+
+	candidates := query.Limit
+	if query.Weight != "" {
+		candidates = store.WeightedPathCandidates
+	}
+
+	// MATCH (a), (b)
+	// WHERE elementId(a) = $from AND elementId(b) = $to
+	// MATCH p = SHORTEST $candidates (a)-[:$Type|...]-{1,$maxDepth}(b)
+	// WHERE all(n IN nodes(p) WHERE single(m IN nodes(p) WHERE m = n))
+	// WITH p, $cost AS cost
+	// RETURN p, cost
+	// ORDER BY cost, length(p)
+	// LIMIT $limit
+	//
+	// The selector and the quantifier can't be parameters, they are ints so
+	// it's safe to write them into the query. The WHERE is applied during the
+	// search, so the candidates are all simple paths.
+	q = cypher.New()
+	cost := "toFloat(length(p))"
+	if query.Weight != "" {
+		cost = "reduce(cost = 0.0, r IN relationships(p) | cost + coalesce(toFloatOrNull(r[" + q.Param("weight", query.Weight) + "]), 1.0))"
+	}
+	q.Raw("MATCH (a), (b) WHERE elementId(a) = " + q.Param("from", query.From) + " AND elementId(b) = " + q.Param("to", query.To) +
+		" MATCH p = SHORTEST " + strconv.Itoa(candidates) + " (a)-" + relation + arrow + "{1," + strconv.Itoa(query.MaxDepth) + "}(b)" +
+		" WHERE all(n IN nodes(p) WHERE single(m IN nodes(p) WHERE m = n))" +
+		" WITH p, " + cost + " AS cost RETURN p, cost ORDER BY cost, length(p)")
+	q.Limit(query.Limit)
+
+	result, err = s.read(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]store.WeightedPath, 0, len(result.Records))
+	for _, record := range result.Records {
+		path, _, err := neo4j.GetRecordValue[neo4j.Path](record, "p")
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, store.WeightedPath{Path: path, Cost: value[float64](record, "cost")})
+	}
+	return paths, nil
+}
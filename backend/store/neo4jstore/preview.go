@@ -0,0 +1,95 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (s *Store) PreviewNodes(ctx context.Context, match store.Object, limit int, sampleSize int) (store.NodePreview, error) {
+	// MATCH (n:$NodeType {$key: $value})
+	// WHERE $filter
+	// WITH n
+	// LIMIT $limit
+	// WITH count(n) AS affected, collect(n)[..$sample] AS sample
+	// RETURN affected, sample
+	q := cypher.New().Match(asNode("n", match))
+	q = limitMatches(nodeWhere(q, "n", match), limit, "n")
+	q = previewReturn(q, "n", "n", sampleSize)
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return store.NodePreview{}, err
+	}
+
+	affected, sample, err := readPreview(result)
+	if err != nil {
+		return store.NodePreview{}, err
+	}
+	preview := store.NodePreview{Affected: affected, Sample: make([]store.Node, 0, len(sample))}
+	for _, value := range sample {
+		if node, isNode := value.(neo4j.Node); isNode {
+			preview.Sample = append(preview.Sample, node)
+		}
+	}
+	return preview, nil
+}
+
+func (s *Store) PreviewRelations(ctx context.Context, pattern store.RelationPattern, limit int, sampleSize int) (store.RelationPreview, error) {
+	// MATCH (n1:$NodeType {$key: $value})-[r:$RelationType {$key: $value}]->(n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// WITH r, n1, n2
+	// LIMIT $limit
+	// WITH count(r) AS affected, collect([r, n1, n2])[..$sample] AS sample
+	// RETURN affected, sample
+	q := cypher.New().Match(relationPattern(pattern))
+	q = limitMatches(relationWhere(q, pattern), limit, "r", "n1", "n2")
+	q = previewReturn(q, "r", "[r, n1, n2]", sampleSize)
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return store.RelationPreview{}, err
+	}
+
+	affected, sample, err := readPreview(result)
+	if err != nil {
+		return store.RelationPreview{}, err
+	}
+	preview := store.RelationPreview{Affected: affected, Sample: make([]store.RelationMatch, 0, len(sample))}
+	for _, value := range sample {
+		row, _ := value.([]any)
+		if len(row) != 3 {
+			continue
+		}
+		relation, _ := row[0].(neo4j.Relationship)
+		origin, _ := row[1].(neo4j.Node)
+		destination, _ := row[2].(neo4j.Node)
+		preview.Sample = append(preview.Sample, store.RelationMatch{Relation: relation, Origin: origin, Destination: destination})
+	}
+	return preview, nil
+}
+
+// previewReturn counts the matched `variable`s and collects a sample of `item`.
+func previewReturn(q *cypher.Query, variable string, item string, sampleSize int) *cypher.Query {
+	return q.
+		With("count("+variable+") AS affected", "collect("+item+")[.."+q.Param("sample", sampleSize)+"] AS sample").
+		Return("affected", "sample")
+}
+
+func readPreview(result *neo4j.EagerResult) (int64, []any, error) {
+	if len(result.Records) == 0 {
+		return 0, nil, nil
+	}
+	record := result.Records[0]
+	affected, _, err := neo4j.GetRecordValue[int64](record, "affected")
+	if err != nil {
+		return 0, nil, err
+	}
+	sample, _, err := neo4j.GetRecordValue[[]any](record, "sample")
+	if err != nil {
+		return 0, nil, err
+	}
+	return affected, sample, nil
+}
@@ -0,0 +1,120 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (s *Store) CreateRelation(ctx context.Context, pattern store.RelationPattern) ([]store.RelationMatch, error) {
+	// MATCH (n1:$NodeType {$key: $value}), (n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// MERGE (n1)-[r:$RelationType {$key: $value}]->(n2)
+	// RETURN r, n1, n2
+	q := cypher.New().Match(asNode("n1", pattern.Origin), asNode("n2", pattern.Destination))
+	q = whereFilters(q, filter.Where(q, "n1", pattern.Origin.Where), filter.Where(q, "n2", pattern.Destination.Where)).
+		Merge(cypher.Node("n1", "", nil).To("r", pattern.Relation.Category, pattern.Relation.Properties, cypher.Node("n2", "", nil))).
+		Return("r", "n1", "n2")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collectRelationMatches(result)
+}
+
+func (s *Store) FindRelations(ctx context.Context, pattern store.RelationPattern, limit int) ([]store.RelationMatch, error) {
+	// MATCH (n1:$NodeType {$key: $value})-[r:$RelationType {$key: $value}]->(n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// RETURN r, n1, n2
+	// LIMIT $limit
+	q := cypher.New().Match(relationPattern(pattern))
+	q = relationWhere(q, pattern).
+		Return("r", "n1", "n2").
+		Limit(limit)
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collectRelationMatches(result)
+}
+
+func (s *Store) UpdateRelations(ctx context.Context, pattern store.RelationPattern, properties map[string]any, limit int) ([]store.Relationship, error) {
+	// MATCH (n1:$NodeType {$key: $value})-[r:$RelationType {$key: $value}]->(n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// SET r.$key = $value
+	// RETURN r
+	q := cypher.New().Match(relationPattern(pattern))
+	q = limitMatches(relationWhere(q, pattern), limit, "r").
+		Set("r", properties).
+		Return("r")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Relationship](result, "r")
+}
+
+func (s *Store) RemoveRelationProperties(ctx context.Context, pattern store.RelationPattern, keys []string) ([]store.Relationship, error) {
+	// MATCH (n1:$NodeType {$key: $value})-[r:$RelationType {$key: $value}]->(n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// REMOVE r.$key
+	// RETURN r
+	q := cypher.New().Match(relationPattern(pattern))
+	q = relationWhere(q, pattern).
+		Remove("r", keys...).
+		Return("r")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return collect[neo4j.Relationship](result, "r")
+}
+
+func (s *Store) DeleteRelations(ctx context.Context, pattern store.RelationPattern, limit int) (int, error) {
+	// MATCH (n1:$NodeType {$key: $value})-[r:$RelationType {$key: $value}]->(n2:$NodeType {$key: $value})
+	// WHERE $filter
+	// WITH r
+	// LIMIT $limit
+	// DELETE r
+	q := cypher.New().Match(relationPattern(pattern))
+	q = limitMatches(relationWhere(q, pattern), limit, "r").
+		Delete("r")
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return result.Summary.Counters().RelationshipsDeleted(), nil
+}
+
+func collectRelationMatches(result *neo4j.EagerResult) ([]store.RelationMatch, error) {
+	matches := make([]store.RelationMatch, 0, len(result.Records))
+	for _, record := range result.Records {
+		relation, _, err := neo4j.GetRecordValue[neo4j.Relationship](record, "r")
+		if err != nil {
+			return nil, err
+		}
+		origin, _, err := neo4j.GetRecordValue[neo4j.Node](record, "n1")
+		if err != nil {
+			return nil, err
+		}
+		destination, _, err := neo4j.GetRecordValue[neo4j.Node](record, "n2")
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, store.RelationMatch{
+			Relation:    relation,
+			Origin:      origin,
+			Destination: destination,
+		})
+	}
+	return matches, nil
+}
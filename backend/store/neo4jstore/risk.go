@@ -0,0 +1,77 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// SupplyNetwork reads the materials, the products and the `NEEDS`
+// relationships between them in one read transaction, and walks them in Go
+// with store.NeededMaterials like memstore does. Matching the paths of every
+// product in Cypher would enumerate all of them.
+func (s *Store) SupplyNetwork(ctx context.Context) (store.SupplyNetwork, error) {
+	network := store.SupplyNetwork{
+		Products:  []store.Node{},
+		Materials: []store.Node{},
+		Providers: make(map[string][]store.Node),
+		Needs:     make(map[string][]string),
+	}
+
+	err := s.readTransaction(ctx, func(tx *Store) error {
+		// MATCH (material:Material)
+		// OPTIONAL MATCH (provider:Provider)-[:PRODUCES]->(material)
+		// RETURN material, collect(DISTINCT provider) AS providers
+		q := cypher.New()
+		q.Raw("MATCH (material:Material)" +
+			" OPTIONAL MATCH (provider:Provider)-[:PRODUCES]->(material)" +
+			" RETURN material, collect(DISTINCT provider) AS providers")
+
+		result, err := tx.read(ctx, q)
+		if err != nil {
+			return err
+		}
+		for _, record := range result.Records {
+			material, _, err := neo4j.GetRecordValue[neo4j.Node](record, "material")
+			if err != nil {
+				return err
+			}
+			network.Materials = append(network.Materials, material)
+			network.Providers[material.ElementId] = listOf[neo4j.Node](record, "providers")
+		}
+
+		// MATCH (product:Product)
+		// RETURN product
+		result, err = tx.read(ctx, cypher.New().Match(cypher.Node("product", "Product", nil)).Return("product"))
+		if err != nil {
+			return err
+		}
+		products, err := collect[neo4j.Node](result, "product")
+		if err != nil {
+			return err
+		}
+
+		// MATCH (n)-[:NEEDS]->(material:Material)
+		// RETURN elementId(n) AS from, elementId(material) AS to
+		q = cypher.New()
+		q.Raw("MATCH (n)-[:NEEDS]->(material:Material) RETURN elementId(n) AS from, elementId(material) AS to")
+		result, err = tx.read(ctx, q)
+		if err != nil {
+			return err
+		}
+		needs := make(map[string][]string)
+		for _, record := range result.Records {
+			from := value[string](record, "from")
+			needs[from] = append(needs[from], value[string](record, "to"))
+		}
+
+		for _, product := range products {
+			network.Products = append(network.Products, product)
+			network.Needs[product.ElementId] = store.NeededMaterials(needs, product.ElementId)
+		}
+		return nil
+	})
+	return network, err
+}
@@ -0,0 +1,65 @@
+package neo4jstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+// salesGroups match the `group` node of every purchase `r`, by SalesGroup.
+// The providers are searched for every purchase, so the depth is bounded.
+var salesGroups = map[store.SalesGroup]string{
+	store.SalesByProduct:  "MATCH (group:Product {id: r.productId})",
+	store.SalesByRetailer: "WITH r, day, retailer AS group",
+	store.SalesByProvider: "MATCH (p:Product {id: r.productId}), (group:Provider)-[:PRODUCES]->()<-[:NEEDS*0.." + strconv.Itoa(store.SalesProviderDepth) + "]-(p)" +
+		" WITH DISTINCT r, day, group",
+}
+
+func (s *Store) DailySales(ctx context.Context, query store.SalesQuery) ([]store.DailySales, error) {
+	// MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer)
+	// WITH r, retailer, left(toString(r.date), 10) AS day
+	// WHERE day IS NOT NULL AND day >= $since AND day <= $until
+	// $group
+	// RETURN day, toString(group.id) AS groupId, group.name AS groupName, count(r) AS sales
+	// ORDER BY day
+	q := cypher.New().
+		Raw("MATCH (c:Consumer)-[r:BUYS_FROM_RETAILER]->(retailer:Retailer)").
+		With("r", "retailer", "left(toString(r.date), 10) AS day")
+	conditions := []string{"day IS NOT NULL"}
+	if query.Since != "" {
+		conditions = append(conditions, "day >= "+q.Param("since", query.Since))
+	}
+	if query.Until != "" {
+		conditions = append(conditions, "day <= "+q.Param("until", query.Until))
+	}
+	q.Where(conditions...)
+
+	if query.GroupBy == "" {
+		q.Return("day", "count(r) AS sales")
+	} else {
+		group, found := salesGroups[query.GroupBy]
+		if !found {
+			return nil, fmt.Errorf("purchases can't be grouped by `%s`", query.GroupBy)
+		}
+		q.Raw(group).Return("day", "toString(group.id) AS groupId", "group.name AS groupName", "count(r) AS sales")
+	}
+	q.OrderBy("day")
+
+	result, err := s.read(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	sales := make([]store.DailySales, 0, len(result.Records))
+	for _, record := range result.Records {
+		sales = append(sales, store.DailySales{
+			Day:       value[string](record, "day"),
+			GroupId:   value[string](record, "groupId"),
+			GroupName: value[string](record, "groupName"),
+			Sales:     value[int64](record, "sales"),
+		})
+	}
+	return sales, nil
+}
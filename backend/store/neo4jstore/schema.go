@@ -0,0 +1,163 @@
+package neo4jstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/cypher"
+	"github.com/ElrohirGT/Proyecto1_DB2/identifier"
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+)
+
+func (s *Store) Constraints(ctx context.Context) ([]store.Constraint, error) {
+	result, err := s.read(ctx, cypher.New().Raw(
+		"SHOW CONSTRAINTS YIELD name, type, entityType, labelsOrTypes, properties RETURN * ORDER BY name",
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := make([]store.Constraint, 0, len(result.Records))
+	for _, record := range result.Records {
+		constraints = append(constraints, store.Constraint{
+			Name:          value[string](record, "name"),
+			Type:          value[string](record, "type"),
+			EntityType:    value[string](record, "entityType"),
+			LabelsOrTypes: listOf[string](record, "labelsOrTypes"),
+			Properties:    listOf[string](record, "properties"),
+		})
+	}
+	return constraints, nil
+}
+
+func (s *Store) CreateUniqueConstraint(ctx context.Context, spec store.IndexSpec) (store.SchemaChange, error) {
+	// CREATE CONSTRAINT $name IF NOT EXISTS
+	// FOR (n:$Label) REQUIRE (n.$key, ...) IS UNIQUE
+	name, on, err := schemaTarget(spec, "unique")
+	if err != nil {
+		return store.SchemaChange{}, err
+	}
+	q := cypher.New()
+	q.Raw(fmt.Sprintf("CREATE CONSTRAINT %s IF NOT EXISTS %s REQUIRE %s IS UNIQUE", identifier.Escape(name), on, indexProperties(q, spec)))
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.SchemaChange{}, err
+	}
+	return store.SchemaChange{Name: name, Created: result.Summary.Counters().ConstraintsAdded() > 0}, nil
+}
+
+func (s *Store) DropConstraint(ctx context.Context, name string) error {
+	if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+		return err
+	}
+
+	result, err := s.run(ctx, cypher.New().Raw("DROP CONSTRAINT "+identifier.Escape(name)+" IF EXISTS"))
+	if err != nil {
+		return err
+	}
+	if result.Summary.Counters().ConstraintsRemoved() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) Indexes(ctx context.Context) ([]store.Index, error) {
+	result, err := s.read(ctx, cypher.New().Raw(
+		"SHOW INDEXES YIELD name, type, entityType, labelsOrTypes, properties, state, owningConstraint RETURN * ORDER BY name",
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]store.Index, 0, len(result.Records))
+	for _, record := range result.Records {
+		indexes = append(indexes, store.Index{
+			Name:             value[string](record, "name"),
+			Type:             value[string](record, "type"),
+			EntityType:       value[string](record, "entityType"),
+			LabelsOrTypes:    listOf[string](record, "labelsOrTypes"),
+			Properties:       listOf[string](record, "properties"),
+			State:            value[string](record, "state"),
+			OwningConstraint: value[string](record, "owningConstraint"),
+		})
+	}
+	return indexes, nil
+}
+
+func (s *Store) CreateIndex(ctx context.Context, spec store.IndexSpec) (store.SchemaChange, error) {
+	// CREATE RANGE INDEX $name IF NOT EXISTS
+	// FOR (n:$Label) ON (n.$key, ...)
+	if spec.Type != store.IndexRange && spec.Type != store.IndexText {
+		return store.SchemaChange{}, fmt.Errorf("unknown index type `%s`", spec.Type)
+	}
+	name, on, err := schemaTarget(spec, strings.ToLower(string(spec.Type)))
+	if err != nil {
+		return store.SchemaChange{}, err
+	}
+	q := cypher.New()
+	q.Raw(fmt.Sprintf("CREATE %s INDEX %s IF NOT EXISTS %s ON (%s)", spec.Type, identifier.Escape(name), on, strings.Join(propertyList(q, spec), ", ")))
+
+	result, err := s.run(ctx, q)
+	if err != nil {
+		return store.SchemaChange{}, err
+	}
+	return store.SchemaChange{Name: name, Created: result.Summary.Counters().IndexesAdded() > 0}, nil
+}
+
+func (s *Store) DropIndex(ctx context.Context, name string) error {
+	if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+		return err
+	}
+
+	result, err := s.run(ctx, cypher.New().Raw("DROP INDEX "+identifier.Escape(name)+" IF EXISTS"))
+	if err != nil {
+		return err
+	}
+	if result.Summary.Counters().IndexesRemoved() == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// schemaTarget returns the name of the constraint or index described by spec,
+// generated from its label, properties and suffix when it's empty, and its
+// `FOR (n:$Label)` clause.
+func schemaTarget(spec store.IndexSpec, suffix string) (name string, on string, err error) {
+	label, err := identifier.Label(spec.Label)
+	if err != nil {
+		return "", "", err
+	}
+	if len(spec.Properties) == 0 {
+		return "", "", errors.New("at least one property is required")
+	}
+
+	name = spec.Name
+	if name == "" {
+		name = strings.ToLower(spec.Label + "_" + strings.Join(spec.Properties, "_") + "_" + suffix)
+	}
+	if err := identifier.Validate(identifier.KindSchemaName, name); err != nil {
+		return "", "", err
+	}
+	return name, "FOR (n:" + label + ")", nil
+}
+
+// indexProperties returns `n.$key` for a single property and `(n.$key, ...)`
+// for several.
+func indexProperties(q *cypher.Query, spec store.IndexSpec) string {
+	properties := propertyList(q, spec)
+	if len(properties) == 1 {
+		return properties[0]
+	}
+	return "(" + strings.Join(properties, ", ") + ")"
+}
+
+func propertyList(q *cypher.Query, spec store.IndexSpec) []string {
+	properties := make([]string, 0, len(spec.Properties))
+	for _, key := range spec.Properties {
+		properties = append(properties, q.Property("n", key))
+	}
+	return properties
+}
@@ -0,0 +1,83 @@
+package neo4jstore
+
+import (
+	"context"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/store"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *Store) WriteTransaction(ctx context.Context, fn func(tx store.GraphStore) error) error {
+	if s.tx != nil {
+		// Already inside a transaction, the outer one decides whether to commit.
+		return fn(s)
+	}
+
+	session := s.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: s.database, AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&Store{driver: s.driver, database: s.database, tx: tx}); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			log.Error().Err(rollbackErr).Msg("Error rolling back transaction!")
+		}
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *Store) ReadTransaction(ctx context.Context, fn func(tx store.GraphStore) error) error {
+	return s.readTransaction(ctx, func(tx *Store) error {
+		return fn(tx)
+	})
+}
+
+// readTransaction runs fn with a store whose queries share a read
+// transaction, so they all see the same snapshot of the graph.
+func (s *Store) readTransaction(ctx context.Context, fn func(tx *Store) error) error {
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	session := s.driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: s.database, AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Close(ctx)
+
+	if err := fn(&Store{driver: s.driver, database: s.database, tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// runInTransaction executes query inside tx and reads the whole result, like
+// neo4j.ExecuteQuery does with neo4j.EagerResultTransformer.
+func runInTransaction(ctx context.Context, tx neo4j.ExplicitTransaction, query string, params map[string]any) (*neo4j.EagerResult, error) {
+	result, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := result.Keys()
+	if err != nil {
+		return nil, err
+	}
+	records, err := result.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &neo4j.EagerResult{Keys: keys, Records: records, Summary: summary}, nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// The amount of entries every ranking keeps when StatisticsFilter.Limit isn't set.
+const (
+	TopProductsLimit          = 3
+	TopProvidersLimit         = 5
+	TopPurchasedProductsLimit = 10
+)
+
+// DateLayout is how dates are written in filters and date properties.
+const DateLayout = "2006-01-02"
+
+// StatisticsFilter narrows the rankings, its zero value ranks everything.
+type StatisticsFilter struct {
+	// Limit is how many entries every ranking keeps.
+	Limit int `json:"limit,omitempty"`
+	// Category only counts the ratings and purchases of products in this category.
+	Category string `json:"category,omitempty"`
+	// Country only counts the providers from this country.
+	Country string `json:"country,omitempty"`
+	// Since and Until bound the `date` of the ratings and purchases, both are
+	// inclusive. Relationships without a date are left out when either is set.
+	Since string `json:"since,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+// LimitOr returns the filter limit, or fallback when it isn't set.
+func (f StatisticsFilter) LimitOr(fallback int) int {
+	if f.Limit > 0 {
+		return f.Limit
+	}
+	return fallback
+}
+
+// InWindow tells if the date property value is between Since and Until.
+func (f StatisticsFilter) InWindow(value any) bool {
+	if f.Since == "" && f.Until == "" {
+		return true
+	}
+	date, isDate := DateOf(value)
+	if !isDate {
+		return false
+	}
+	day := date.Format(DateLayout)
+	return (f.Since == "" || day >= f.Since) && (f.Until == "" || day <= f.Until)
+}
+
+// DateOf reads a date property, which is a Neo4j date or date time, or a
+// string starting with `YYYY-MM-DD`. The schema validates date properties
+// with it too, so every value accepted on a write can be read back.
+func DateOf(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case dbtype.Date:
+		return v.Time(), true
+	case dbtype.LocalDateTime:
+		return v.Time(), true
+	case time.Time:
+		return v, true
+	case string:
+		if len(v) < len(DateLayout) {
+			return time.Time{}, false
+		}
+		date, err := time.Parse(DateLayout, v[:len(DateLayout)])
+		return date, err == nil
+	}
+	return time.Time{}, false
+}
+
+// SalesGroup is what the purchases of a sales series are grouped by.
+type SalesGroup string
+
+const (
+	SalesByProduct  SalesGroup = "product"
+	SalesByRetailer SalesGroup = "retailer"
+	// SalesByProvider counts a purchase for every provider of the product or
+	// of any material it needs, up to SalesProviderDepth `NEEDS` levels away.
+	SalesByProvider SalesGroup = "provider"
+)
+
+// SalesProviderDepth is how many `NEEDS` levels are followed to find the
+// providers of a purchased product.
+const SalesProviderDepth = 6
+
+// SalesQuery selects the `BUYS_FROM_RETAILER` relationships counted by
+// DailySales. Since and Until bound their `date` like in StatisticsFilter.
+type SalesQuery struct {
+	// GroupBy is empty to count every purchase together.
+	GroupBy SalesGroup
+	Since   string
+	Until   string
+}
+
+// DailySales is how many purchases of a group were made on Day, which is
+// written as `YYYY-MM-DD`. GroupId and GroupName are the `id` and `name` of
+// the product, retailer or provider, they're empty when not grouping.
+type DailySales struct {
+	Day       string
+	GroupId   string
+	GroupName string
+	Sales     int64
+}
@@ -0,0 +1,371 @@
+// Package store defines the GraphStore interface the HTTP handlers use to talk
+// to the supply chain graph, so they don't depend on a specific database.
+//
+// The neo4jstore package implements it on top of Neo4j and the memstore
+// package implements it with plain Go maps.
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ElrohirGT/Proyecto1_DB2/filter"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// ErrUnsupported is returned when a store can't perform an operation.
+var ErrUnsupported = errors.New("operation not supported by this store")
+
+// ErrNotFound is returned when no node or relationship has the requested element ID.
+var ErrNotFound = errors.New("element not found")
+
+type Node = dbtype.Node
+type Relationship = dbtype.Relationship
+type Path = dbtype.Path
+
+// Object identifies a node or a relationship by its label (or type) and
+// the properties it must have.
+type Object struct {
+	Category   string
+	Properties map[string]any
+	// Where optionally narrows the match with operators such as `$gt` or `$in`.
+	Where filter.Expr
+}
+
+// RelationPattern describes an `(Origin)-[Relation]->(Destination)` match.
+type RelationPattern struct {
+	Origin      Object
+	Relation    Object
+	Destination Object
+}
+
+// NodeMerge is the result of an upsert, Created tells if the node is new.
+type NodeMerge struct {
+	Node    Node
+	Created bool
+}
+
+// NodeUpdate holds the properties of a node before and after being updated.
+type NodeUpdate struct {
+	Node   Node
+	Before map[string]any
+	After  map[string]any
+}
+
+// RelationMatch is a relationship together with its start and end nodes.
+type RelationMatch struct {
+	Relation    Relationship
+	Origin      Node
+	Destination Node
+}
+
+// NodeListing describes a page of the nodes matching Match.
+type NodeListing struct {
+	Match Object
+	// SortBy is the property used to sort the nodes, when empty they're sorted by element ID.
+	SortBy     string
+	Descending bool
+	Skip       int
+	Limit      int
+}
+
+// NodePage is a page of nodes along with the total amount of matches.
+type NodePage struct {
+	Nodes []Node
+	Total int64
+}
+
+// ImportRow is a row of a bulk import, Line locates it in the imported file.
+type ImportRow struct {
+	Line       int
+	Properties map[string]any
+	// From and To hold the key values of the nodes a relationship row connects.
+	From any
+	To   any
+}
+
+// ImportEndpoint finds the nodes with Label whose Key property has a given value.
+type ImportEndpoint struct {
+	Label string
+	Key   string
+}
+
+// RelationImport describes the relationships created by a bulk import.
+type RelationImport struct {
+	Type        string
+	Origin      ImportEndpoint
+	Destination ImportEndpoint
+}
+
+// ExportFilter selects the exported nodes by label and relationships by type,
+// an empty list selects all of them.
+type ExportFilter struct {
+	Labels []string
+	Types  []string
+}
+
+// IndexType is the kind of index created over node properties.
+type IndexType string
+
+const (
+	IndexRange IndexType = "RANGE"
+	// IndexText indexes a single string property for `CONTAINS` and `ENDS WITH` searches.
+	IndexText IndexType = "TEXT"
+)
+
+// IndexSpec describes a uniqueness constraint or an index over the properties
+// of the nodes with Label. Type is only used by indexes.
+type IndexSpec struct {
+	Name       string
+	Type       IndexType
+	Label      string
+	Properties []string
+}
+
+// SchemaChange tells the name of a created constraint or index, Created is
+// false when it already existed.
+type SchemaChange struct {
+	Name    string
+	Created bool
+}
+
+// Constraint is a constraint as reported by the database.
+type Constraint struct {
+	Name          string
+	Type          string
+	EntityType    string
+	LabelsOrTypes []string
+	Properties    []string
+}
+
+// Index is an index as reported by the database.
+type Index struct {
+	Name          string
+	Type          string
+	EntityType    string
+	LabelsOrTypes []string
+	Properties    []string
+	State         string
+	// OwningConstraint is the name of the constraint backed by the index, if any.
+	OwningConstraint string
+}
+
+// NodePreview is what a write over the matched nodes would affect.
+type NodePreview struct {
+	Affected int64
+	Sample   []Node
+}
+
+// RelationPreview is what a write over the matched relationships would affect.
+type RelationPreview struct {
+	Affected int64
+	Sample   []RelationMatch
+}
+
+// BillOfMaterials is the part of the graph reachable from Product through
+// `NEEDS` relationships, up to a given amount of hops. Only materials are
+// followed, a product needed by Product doesn't pass on its materials. The
+// relationships may form cycles.
+type BillOfMaterials struct {
+	Product   Node
+	Materials []Node
+	Needs     []Relationship
+	// Providers maps the element ID of the product and of every material to
+	// the providers that produce it.
+	Providers map[string][]Node
+}
+
+// Impact lists what's downstream of a provider or material: the materials and
+// products that need it directly or through other materials, and the
+// retailers and consumers of those products.
+type Impact struct {
+	Source    Node
+	Materials []Node
+	Products  []Node
+	Retailers []Node
+	Consumers []Node
+}
+
+// SupplyNetwork is every product and material along with who produces them.
+type SupplyNetwork struct {
+	Products  []Node
+	Materials []Node
+	// Providers maps the element ID of every material to the providers that
+	// produce it.
+	Providers map[string][]Node
+	// Needs maps the element ID of every product to the element IDs of the
+	// materials it needs, directly or through other materials. Other nodes
+	// along the `NEEDS` relationships aren't followed.
+	Needs map[string][]string
+}
+
+// WeightedPathCandidates is how many of the paths with the fewest
+// relationships are compared by their cost when a PathQuery has a Weight,
+// which keeps the search from enumerating every path.
+const WeightedPathCandidates = 100
+
+// PathQuery describes the routes searched between the nodes with element IDs
+// From and To. Only simple paths, without repeated nodes, of at most MaxDepth
+// relationships are considered.
+type PathQuery struct {
+	From string
+	To   string
+	// Types restricts the relationships followed, any type is followed when empty.
+	Types []string
+	// Directed only follows relationships from their start to their end node.
+	Directed bool
+	MaxDepth int
+	Limit    int
+	// Weight is the relationship property added up as the cost of a path,
+	// relationships without a numeric value weigh 1. When empty the cost is
+	// the amount of relationships. The cheapest paths are picked among the
+	// WeightedPathCandidates with the fewest relationships.
+	Weight string
+}
+
+// WeightedPath is a path along with its cost.
+type WeightedPath struct {
+	Path Path
+	Cost float64
+}
+
+type ProductRating struct {
+	Name          string  `json:"name"`
+	AverageRating float64 `json:"average_rating"`
+}
+
+type ProviderPopularity struct {
+	Name       string `json:"name"`
+	Popularity int64  `json:"popularity"`
+}
+
+type PurchasedProduct struct {
+	ProductName string `json:"product_name"`
+	ProductId   string `json:"product_id"`
+	Purchases   int64  `json:"purchases"`
+}
+
+// Statistics are the rankings shown on the Stats page.
+type Statistics struct {
+	TopProducts          []ProductRating      `json:"top_products"`
+	TopProviders         []ProviderPopularity `json:"top_providers"`
+	TopPurchasedProducts []PurchasedProduct   `json:"top_purchased_products"`
+}
+
+// GraphStore is implemented by every graph backend.
+//
+// A `limit` lower or equal than zero means no limit. Methods addressing an
+// element ID return ErrNotFound when it doesn't exist. Setting a property to
+// nil removes it, like it does in Cypher.
+type GraphStore interface {
+	CreateNode(ctx context.Context, label string, properties map[string]any) (Node, error)
+	// MergeNode finds the node with the given label and keys or creates it,
+	// either way the given properties are set on it.
+	MergeNode(ctx context.Context, label string, keys map[string]any, properties map[string]any) (NodeMerge, error)
+	FindNodes(ctx context.Context, match Object, limit int) ([]Node, error)
+	// ListNodes returns a sorted page of the matched nodes.
+	ListNodes(ctx context.Context, listing NodeListing) (NodePage, error)
+	// UpdateNodes sets the given properties on every matched node.
+	UpdateNodes(ctx context.Context, match Object, properties map[string]any, limit int) ([]NodeUpdate, error)
+	// RemoveNodeProperties removes the given keys from every matched node.
+	RemoveNodeProperties(ctx context.Context, match Object, keys []string, limit int) ([]Node, error)
+	// DeleteNodes deletes the matched nodes along with their relationships.
+	DeleteNodes(ctx context.Context, match Object, limit int) ([]Node, error)
+
+	// PreviewNodes counts the nodes a write with the same match and limit would
+	// affect and returns up to sampleSize of them, without changing the graph.
+	PreviewNodes(ctx context.Context, match Object, limit int, sampleSize int) (NodePreview, error)
+
+	// GetNode returns the node with the given element ID.
+	GetNode(ctx context.Context, elementId string) (Node, error)
+	// UpdateNode sets the given properties on the node with the given element ID.
+	UpdateNode(ctx context.Context, elementId string, properties map[string]any) (NodeUpdate, error)
+	// DeleteNode deletes the node with the given element ID along with its relationships.
+	DeleteNode(ctx context.Context, elementId string) (Node, error)
+
+	// CreateRelation creates the relationship between the matched nodes if it doesn't exist yet.
+	CreateRelation(ctx context.Context, pattern RelationPattern) ([]RelationMatch, error)
+	FindRelations(ctx context.Context, pattern RelationPattern, limit int) ([]RelationMatch, error)
+	// CreateRelationBetween creates a relationship between the nodes with the given element IDs.
+	CreateRelationBetween(ctx context.Context, originId string, destinationId string, relationType string, properties map[string]any) (RelationMatch, error)
+	// UpdateRelations sets the given properties on every matched relationship.
+	UpdateRelations(ctx context.Context, pattern RelationPattern, properties map[string]any, limit int) ([]Relationship, error)
+	// RemoveRelationProperties removes the given keys from every matched relationship.
+	RemoveRelationProperties(ctx context.Context, pattern RelationPattern, keys []string) ([]Relationship, error)
+	// DeleteRelations deletes the matched relationships and returns how many were deleted.
+	DeleteRelations(ctx context.Context, pattern RelationPattern, limit int) (int, error)
+
+	// PreviewRelations is like PreviewNodes for relationships.
+	PreviewRelations(ctx context.Context, pattern RelationPattern, limit int, sampleSize int) (RelationPreview, error)
+
+	// GetRelation returns the relationship with the given element ID and its nodes.
+	GetRelation(ctx context.Context, elementId string) (RelationMatch, error)
+	// UpdateRelation sets the given properties on the relationship with the given element ID.
+	UpdateRelation(ctx context.Context, elementId string, properties map[string]any) (Relationship, error)
+	// DeleteRelation deletes the relationship with the given element ID.
+	DeleteRelation(ctx context.Context, elementId string) (Relationship, error)
+
+	// ImportNodes creates a node with the given label for every row and returns how many were created.
+	ImportNodes(ctx context.Context, label string, rows []ImportRow) (int, error)
+	// ImportRelations creates a relationship for every row between the nodes
+	// matching its From and To values, and returns the lines of the rows that
+	// matched both nodes.
+	ImportRelations(ctx context.Context, spec RelationImport, rows []ImportRow) ([]int, error)
+
+	// Export streams the selected nodes to onNode and then the selected
+	// relationships between them to onRelation, reading a consistent snapshot
+	// of the graph. It stops at the first error returned by a callback.
+	Export(ctx context.Context, filter ExportFilter, onNode func(Node) error, onRelation func(Relationship) error) error
+
+	// WriteTransaction calls fn with a store whose writes are committed together
+	// when fn returns nil and are all rolled back when it returns an error.
+	WriteTransaction(ctx context.Context, fn func(tx GraphStore) error) error
+	// ReadTransaction calls fn with a store whose reads all see the graph as
+	// it was when the transaction started.
+	ReadTransaction(ctx context.Context, fn func(tx GraphStore) error) error
+
+	// Constraints lists every constraint of the database.
+	Constraints(ctx context.Context) ([]Constraint, error)
+	// CreateUniqueConstraint makes the spec properties unique among the nodes
+	// with its label, unless an equivalent constraint already exists.
+	CreateUniqueConstraint(ctx context.Context, spec IndexSpec) (SchemaChange, error)
+	// DropConstraint drops the constraint with the given name, or returns
+	// ErrNotFound if there's none.
+	DropConstraint(ctx context.Context, name string) error
+	// Indexes lists every index of the database.
+	Indexes(ctx context.Context) ([]Index, error)
+	// CreateIndex creates the index unless an equivalent one already exists.
+	CreateIndex(ctx context.Context, spec IndexSpec) (SchemaChange, error)
+	// DropIndex drops the index with the given name, or returns ErrNotFound if there's none.
+	DropIndex(ctx context.Context, name string) error
+
+	// Introspect describes the labels and relationship types in the graph,
+	// property types are inferred from up to sampleSize elements of each.
+	Introspect(ctx context.Context, sampleSize int) (GraphSchema, error)
+
+	// ProductHistory returns the paths from every provider to the product or its materials.
+	ProductHistory(ctx context.Context, productId string) ([]Path, error)
+	// BillOfMaterials follows the `NEEDS` relationships of the product with the
+	// given id up to depth hops, it returns ErrNotFound if there's no such product.
+	BillOfMaterials(ctx context.Context, productId string, depth int) (BillOfMaterials, error)
+	// Impact walks downstream from the node with the given label and id,
+	// which is a `Provider` or a `Material`, following at most maxDepth
+	// `NEEDS` relationships. It returns ErrNotFound if there's no such node.
+	Impact(ctx context.Context, label string, id string, maxDepth int) (Impact, error)
+	// SupplyNetwork returns the products and materials along with their
+	// providers, for the supply risk analysis.
+	SupplyNetwork(ctx context.Context) (SupplyNetwork, error)
+	// FindPaths returns up to query.Limit paths sorted by cost and then
+	// length. It returns ErrNotFound if either node doesn't exist.
+	FindPaths(ctx context.Context, query PathQuery) ([]WeightedPath, error)
+	// TopProducts ranks the products by their average rating, counting only
+	// what the filter selects. The other rankings do the same.
+	TopProducts(ctx context.Context, filter StatisticsFilter) ([]ProductRating, error)
+	// TopProviders ranks the providers by how many retailers prefer them.
+	TopProviders(ctx context.Context, filter StatisticsFilter) ([]ProviderPopularity, error)
+	// TopPurchasedProducts ranks the products by how many times they were bought.
+	TopPurchasedProducts(ctx context.Context, filter StatisticsFilter) ([]PurchasedProduct, error)
+	// DailySales counts the purchases made every day, purchases without a
+	// date are left out.
+	DailySales(ctx context.Context, query SalesQuery) ([]DailySales, error)
+}
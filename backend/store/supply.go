@@ -0,0 +1,27 @@
+package store
+
+// NeededMaterials walks needs breadth first from the product with element ID
+// productId and returns the element IDs of the materials it reaches. needs
+// maps the element ID of every product and material to the materials it
+// needs, so other nodes are never followed. Every material is expanded once,
+// which ends the walk of cycles.
+func NeededMaterials(needs map[string][]string, productId string) []string {
+	materials := []string{}
+	expanded := map[string]bool{productId: true}
+	frontier := []string{productId}
+	for len(frontier) > 0 {
+		var next []string
+		for _, current := range frontier {
+			for _, material := range needs[current] {
+				if expanded[material] {
+					continue
+				}
+				expanded[material] = true
+				materials = append(materials, material)
+				next = append(next, material)
+			}
+		}
+		frontier = next
+	}
+	return materials
+}
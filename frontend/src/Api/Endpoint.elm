@@ -1,8 +1,9 @@
-module Api.Endpoint exposing (GetHistoryResponse, getHistory, getHistoryResponseDecoder, GetStatsResponse, getStats, getStatsResponseDecoder, request)
+module Api.Endpoint exposing (Error(..), ErrorResponse, errorResponseDecoder, expectJson, GetHistoryResponse, getHistory, getHistoryResponseDecoder, GetStatsResponse, getStats, getStatsResponseDecoder, request)
 
 import Http
-import Json.Decode exposing (Decoder, field, list, map3, succeed)
-import Json.Decode.Pipeline exposing (required)
+import Json.Decode exposing (Decoder, field, list, map3, succeed, value)
+import Json.Decode.Pipeline exposing (optional, required)
+import Json.Encode
 import Models.Node exposing (Node, nodeDecoder)
 import Models.Relation exposing (Relation, relationDecoder)
 import Models.Product exposing (Product, productDecoder)
@@ -35,6 +36,59 @@ request config =
         }
 
 
+-- ERRORS
+
+{-| Body of every failed request, `code` is one of INVALID_JSON, MISSING_FIELD,
+INVALID_FIELD, NOT_FOUND, METHOD_NOT_ALLOWED, DB_ERROR or INTERNAL_ERROR.
+-}
+type alias ErrorResponse =
+    { code : String
+    , message : String
+    , details : Json.Decode.Value
+    , requestId : String
+    }
+
+errorResponseDecoder : Decoder ErrorResponse
+errorResponseDecoder =
+    succeed ErrorResponse
+        |> required "code" Json.Decode.string
+        |> required "message" Json.Decode.string
+        |> optional "details" value Json.Encode.null
+        |> optional "requestId" Json.Decode.string ""
+
+type Error
+    = HttpError Http.Error
+    | ApiError Int ErrorResponse
+
+{-| Like `Http.expectJson` but decodes the error body the API sends on a bad status.
+-}
+expectJson : (Result Error a -> msg) -> Decoder a -> Http.Expect msg
+expectJson toMsg decoder =
+    Http.expectStringResponse toMsg <|
+        \response ->
+            case response of
+                Http.BadUrl_ badUrl ->
+                    Err (HttpError (Http.BadUrl badUrl))
+
+                Http.Timeout_ ->
+                    Err (HttpError Http.Timeout)
+
+                Http.NetworkError_ ->
+                    Err (HttpError Http.NetworkError)
+
+                Http.BadStatus_ metadata body ->
+                    case Json.Decode.decodeString errorResponseDecoder body of
+                        Ok errorResponse ->
+                            Err (ApiError metadata.statusCode errorResponse)
+
+                        Err _ ->
+                            Err (HttpError (Http.BadStatus metadata.statusCode))
+
+                Http.GoodStatus_ _ body ->
+                    Json.Decode.decodeString decoder body
+                        |> Result.mapError (HttpError << Http.BadBody << Json.Decode.errorToString)
+
+
 -- TYPES
 
 type Endpoint
@@ -56,24 +110,19 @@ getHistory : String -> Endpoint
 getHistory productId =
     url [ "history" ] [ string "ProductId" productId ]
 
+{-| Every provider, material and product on a path to the product, each one
+listed once.
+-}
 type alias GetHistoryResponse =
-    { values :
-        List
-            { nodes : List Node
-            , relationships : List Relation
-            }
+    { nodes : List Node
+    , relationships : List Relation
     }
 
 getHistoryResponseDecoder : Decoder GetHistoryResponse
 getHistoryResponseDecoder =
     succeed GetHistoryResponse
-        |> required "Values"
-            (list
-                (succeed (\nodes relationships -> { nodes = nodes, relationships = relationships })
-                    |> required "Nodes" (list nodeDecoder)
-                    |> required "Relationships" (list relationDecoder)
-                )
-            )
+        |> required "nodes" (list nodeDecoder)
+        |> required "relationships" (list relationDecoder)
 
 
 -- GET /stats endpoint
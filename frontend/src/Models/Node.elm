@@ -1,20 +1,20 @@
 module Models.Node exposing (..)
 
 import Dict exposing (Dict)
-import Json.Decode as Decode exposing (dict, int, list, string, value)
+import Json.Decode as Decode exposing (dict, list, string, value)
 import Json.Decode.Pipeline exposing (required)
 
 
 type alias Node =
-    { id : Int
+    { elementId : String
     , labels : List String
-    , props : Dict String Decode.Value
+    , properties : Dict String Decode.Value
     }
 
 
 nodeDecoder : Decode.Decoder Node
 nodeDecoder =
     Decode.succeed Node
-        |> required "Id" int
-        |> required "Labels" (list string)
-        |> required "Props" (dict value)
+        |> required "elementId" string
+        |> required "labels" (list string)
+        |> required "properties" (dict value)
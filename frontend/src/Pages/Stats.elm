@@ -3,7 +3,7 @@ module Pages.Stats exposing (..)
 import Html.Styled exposing (Html, div, h1, text, ul, li, section)
 import Html.Styled.Attributes exposing (class)
 import Http
-import Api.Endpoint exposing (getStats, getStatsResponseDecoder, GetStatsResponse, request)
+import Api.Endpoint exposing (Error, expectJson, getStats, getStatsResponseDecoder, GetStatsResponse, request)
 import Models.Product exposing (Product)
 import Models.Provider exposing (Provider)
 import Models.PurchasedProduct exposing (PurchasedProduct)
@@ -35,7 +35,7 @@ init =
 
 type Msg
     = FetchStats
-    | StatsReceived (Result Http.Error GetStatsResponse)
+    | StatsReceived (Result Error GetStatsResponse)
 
 update : Msg -> Model -> ( Model, Cmd Msg )
 update msg model =
@@ -73,7 +73,7 @@ fetchStats =
         , tracker = Nothing
         , headers = []
         , body = Http.emptyBody
-        , expect = Http.expectJson StatsReceived getStatsResponseDecoder
+        , expect = expectJson StatsReceived getStatsResponseDecoder
         }
 
 
@@ -1,6 +1,6 @@
 module Pages.Trace exposing (..)
 
-import Api.Endpoint exposing (GetHistoryResponse, getHistory, getHistoryResponseDecoder, request)
+import Api.Endpoint exposing (Error(..), GetHistoryResponse, expectJson, getHistory, getHistoryResponseDecoder, request)
 import Css exposing (alignItems, backgroundColor, bold, border, center, color, column, displayFlex, flexDirection, fontWeight, height, justifyContent, none, padding2, px, row, textAlign, textDecoration, verticalAlign, width, zero)
 import Dict
 import Html.Styled exposing (a, button, div, h1, h2, input, li, ol, p, pre, text)
@@ -22,11 +22,11 @@ type alias Producers =
 
 
 type alias HistoryState =
-    Result Http.Error Producers
+    Result Error Producers
 
 
 type alias APIResponse =
-    Result Http.Error GetHistoryResponse
+    Result Error GetHistoryResponse
 
 
 type alias Model =
@@ -38,7 +38,7 @@ type alias Model =
 
 exampleResponse : String
 exampleResponse =
-    "{\"Values\":[{\"Nodes\":[{\"Id\":4152,\"ElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:4152\",\"Labels\":[\"Provider\"],\"Props\":{\"created_at\":\"05/08/2000\",\"email\":\"ksenchenkoi8@issuu.com\",\"id\":\"657\",\"name\":\"Feeney-Ward\",\"owner\":\"Kit Senchenko\"}},{\"Id\":2776,\"ElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:2776\",\"Labels\":[\"Product\"],\"Props\":{\"brand\":\"Vertex\",\"category\":\"Electronics\",\"id\":\"5\",\"name\":\"Smartphone\",\"weight\":\"3.24\"}}],\"Relationships\":[{\"Id\":1155175503443791928,\"ElementId\":\"5:5eaea7dc-9320-449f-993e-45d993464520:1155175503443791928\",\"StartId\":4152,\"StartElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:4152\",\"EndId\":2776,\"EndElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:2776\",\"Type\":\"PRODUCES\",\"Props\":{\"max_quantity\":31,\"since\":{},\"speed\":2}}]}],\"Keys\":[\"p1\"]}"
+    "{\"nodes\":[{\"elementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:4152\",\"labels\":[\"Provider\"],\"properties\":{\"created_at\":\"05/08/2000\",\"email\":\"ksenchenkoi8@issuu.com\",\"id\":\"657\",\"name\":\"Feeney-Ward\",\"owner\":\"Kit Senchenko\"}},{\"elementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:2776\",\"labels\":[\"Product\"],\"properties\":{\"brand\":\"Vertex\",\"category\":\"Electronics\",\"id\":\"5\",\"name\":\"Smartphone\",\"weight\":\"3.24\"}}],\"relationships\":[{\"elementId\":\"5:5eaea7dc-9320-449f-993e-45d993464520:1155175503443791928\",\"type\":\"PRODUCES\",\"properties\":{\"max_quantity\":31,\"since\":\"2021-03-14\",\"speed\":2},\"startElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:4152\",\"endElementId\":\"4:5eaea7dc-9320-449f-993e-45d993464520:2776\"}]}"
 
 
 init : ( Model, Cmd Msg )
@@ -81,7 +81,7 @@ update msg model =
                 , tracker = Nothing
                 , headers = []
                 , body = Http.emptyBody
-                , expect = Http.expectJson GotHistory getHistoryResponseDecoder
+                , expect = expectJson GotHistory getHistoryResponseDecoder
                 }
             )
 
@@ -95,26 +95,21 @@ update msg model =
                     let
                         responseToProducerMapper : GetHistoryResponse -> Producers
                         responseToProducerMapper historyResponse =
-                            historyResponse.values
-                                |> List.map
-                                    (\value ->
-                                        value.nodes
-                                            |> List.filterMap
-                                                (\n ->
-                                                    if List.any (\l -> l == "Provider") n.labels then
-                                                        Dict.get "name" n.props
-                                                            |> Maybe.andThen
-                                                                (\nameValue ->
-                                                                    nameValue
-                                                                        |> Json.Decode.decodeValue Json.Decode.string
-                                                                        |> Result.toMaybe
-                                                                )
-
-                                                    else
-                                                        Nothing
-                                                )
+                            historyResponse.nodes
+                                |> List.filterMap
+                                    (\n ->
+                                        if List.any (\l -> l == "Provider") n.labels then
+                                            Dict.get "name" n.properties
+                                                |> Maybe.andThen
+                                                    (\nameValue ->
+                                                        nameValue
+                                                            |> Json.Decode.decodeValue Json.Decode.string
+                                                            |> Result.toMaybe
+                                                    )
+
+                                        else
+                                            Nothing
                                     )
-                                |> List.concat
                     in
                     Result.map responseToProducerMapper apiResponse
             in
@@ -195,7 +190,7 @@ view model =
                                             Debug.log "HTTP ERROR: " error
                                     in
                                     case error of
-                                        Http.BadBody debugError ->
+                                        HttpError (Http.BadBody debugError) ->
                                             header
                                                 ++ [ pre
                                                         [ css
@@ -206,8 +201,8 @@ view model =
                                                         [ text debugError ]
                                                    ]
 
-                                        Http.BadStatus status ->
-                                            if status == 404 then
+                                        ApiError _ apiError ->
+                                            if apiError.code == "NOT_FOUND" then
                                                 header
                                                     ++ [ div
                                                             [ css